@@ -1,9 +1,12 @@
 package main // Declares that this file is part of the main package
 
 import (
+	"crypto/ecdh"                          // Import the "crypto/ecdh" package for the seed key exchange
+	"crypto/rand"                          // Import the "crypto/rand" package for ephemeral exchange keys
 	"fmt"                                  // Import the "fmt" package for formatted I/O
 	"sync"                                 // Import the "sync" package for synchronization primitives
 	HMAC_env "tmp/src/HMAC/core"           // Import the HMAC core package and alias it as "HMAC_env"
+	secret_types "tmp/src/Secret/types"    // Import the shared SecretKey type and alias it as "secret_types"
 	seed_env "tmp/src/SeedGeneration/core" // Import the SeedGeneration core package and alias it as "seed_env"
 )
 
@@ -15,16 +18,42 @@ func printMsg(who string, message string) { // Define a function named printMsg
 	}
 }
 
+const seedPhrase = "jack" // Phrase both ends mix into the main seed
+
+func deriveSeed(exchangeKey *ecdh.PrivateKey, peer string) *secret_types.SecretKey { // Derive the main seed from the X25519 key exchange
+	peerKey, err := ecdh.X25519().NewPublicKey([]byte(peer)) // Parse the public key of the other end
+	if err != nil {
+		panic(err)
+	}
+	shared, err := exchangeKey.ECDH(peerKey) // Compute the shared secret
+	if err != nil {
+		panic(err)
+	}
+	secret := secret_types.NewSecretKey(shared) // Wrap the shared secret so it stays out of logs
+	defer secret.Destroy()                      // Wipe the shared secret once the seed is derived
+	for i := range shared {
+		shared[i] = 0 // Wipe the unwrapped copy
+	}
+	return seed_env.NewSeedGenerator(seedPhrase).Derive(secret)
+}
+
+func newExchangeKey() *ecdh.PrivateKey { // Generate an ephemeral X25519 key for the seed exchange
+	exchangeKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return exchangeKey
+}
+
 func client(clientSocket chan string, serverSocket chan string, wg *sync.WaitGroup) { // Define a function named client with three parameters
 	defer wg.Done() // Decrement the WaitGroup counter when this function exits
 
-	// Generating the main seed
-	mainSeed := seed_env.NewSeedGenerator("jack").Generate() // Generate a main seed using the SeedGenerator with a specified username
-	obj := HMAC_env.NewHMACClient("sha256", mainSeed, 1)     // Create a new HMACClient object with SHA-256 hash algorithm, main seed, and iteration count
-	obj.InitDecryptDict()                                    // Initialize the decryption dictionary for the HMACClient
-
-	// Sending the main seed to the server through the serverSocket channel
-	serverSocket <- string(mainSeed) // Convert the main seed to a string and send it to the serverSocket channel
+	// Exchanging public keys with the server, so that both ends derive the main seed without sending it
+	exchangeKey := newExchangeKey()                         // Generate an ephemeral key for the exchange
+	serverSocket <- string(exchangeKey.PublicKey().Bytes()) // Send the public key to the serverSocket channel
+	mainSeed := deriveSeed(exchangeKey, <-clientSocket)     // Derive the main seed with the public key of the server
+	obj := HMAC_env.NewHMACClient("sha256", mainSeed, 1)    // Create a new HMACClient object with SHA-256 hash algorithm, main seed, and iteration count
+	obj.InitDecryptDict()                                   // Initialize the decryption dictionary for the HMACClient
 
 	// Checking if the server has successfully received the seed
 	if <-clientSocket == obj.EncryptMessage("") { // Wait for a response from the server via the clientSocket channel and compare it with an encrypted empty message
@@ -43,11 +72,14 @@ func client(clientSocket chan string, serverSocket chan string, wg *sync.WaitGro
 func server(serverSocket chan string, clientSocket chan string, wg *sync.WaitGroup) { // Define a function named server with three parameters
 	defer wg.Done() // Decrement the WaitGroup counter when this function exits
 
-	// Receiving the main seed from the client through the serverSocket channel
-	mainSeed := <-serverSocket // Receive the main seed from the client via the serverSocket channel
+	// Exchanging public keys with the client, so that both ends derive the main seed without sending it
+	clientKey := <-serverSocket                             // Receive the public key of the client via the serverSocket channel
+	exchangeKey := newExchangeKey()                         // Generate an ephemeral key for the exchange
+	clientSocket <- string(exchangeKey.PublicKey().Bytes()) // Send the public key to the clientSocket channel
+	mainSeed := deriveSeed(exchangeKey, clientKey)          // Derive the main seed with the public key of the client
 
-	// Create a new HMACClient object with SHA-256 hash algorithm, received main seed, and iteration count
-	obj := HMAC_env.NewHMACClient("sha256", []byte(mainSeed), 1)
+	// Create a new HMACClient object with SHA-256 hash algorithm, derived main seed, and iteration count
+	obj := HMAC_env.NewHMACClient("sha256", mainSeed, 1)
 	obj.InitDecryptDict() // Initialize the decryption dictionary for the HMACClient
 
 	// Sending an empty message to the client as acknowledgment
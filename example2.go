@@ -1,44 +1,67 @@
 package main // Declares that this file is part of the main package
 
 import (
-	"fmt" // Import the "fmt" package for formatted I/O
-	"sync" // Import the "sync" package for synchronization primitives
-	zkx "tmp/src/ZeroKnowledge/core" // Import the ZeroKnowledge core package and alias it as "zkx"
+	"fmt"                                     // Import the "fmt" package for formatted I/O
+	"sync"                                    // Import the "sync" package for synchronization primitives
+	"time"                                    // Import the "time" package for token and proof lifetimes
+	zkx "tmp/src/ZeroKnowledge/core"          // Import the ZeroKnowledge core package and alias it as "zkx"
 	zkx_models "tmp/src/ZeroKnowledge/models" // Import the ZeroKnowledge models package and alias it as "zkx_models"
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import the ZeroKnowledge types package and alias it as "zkx_types"
 )
 
 var DEBUG = true // Define a global variable DEBUG and set it to true
 
+var serverPassword = zkx_types.NewSecretKey([]byte("SecretServerPassword")) // Set the server password
+
+const audience = "example2" // Audience the login proof is created for
+
 func printMsg(who string, message string) { // Define a function named printMsg that takes two string parameters
 	if DEBUG { // If DEBUG is true, execute the following block
 		fmt.Printf("[%s] %s\n", who, message) // Print a formatted message to standard output
 	}
 }
 
-func client(clientSocket chan string, serverSocket chan string, wg *sync.WaitGroup) { // Define a function named client with three parameters
+func client(clientSocket chan string, serverSocket chan string, pinned zkx_models.ZeroKnowledgeSignature, wg *sync.WaitGroup) { // Define a function named client, pinned is the registered server signature
 	defer wg.Done() // Decrement the WaitGroup counter when this function exits
 
 	// Create a ZeroKnowledge object for the client with specified curve and hash algorithm
-	clientObject := zkx.New("Ed25519", "blake2b", nil, "HB2B", 16)
+	clientObject, err := zkx.New("secp256k1", "sha3_256", nil, "HS256", 16)
+	if err != nil {
+		panic(err)
+	}
 
 	// Generate a signature for the client identity
-	identity := "John"
+	identity := zkx_types.NewSecretKey([]byte("John"))
 	signature := clientObject.CreateSignature(identity)
 
-	// Send the signature to the server through the serverSocket channel
-	serverSocket <- zkx_models.ZeroKnowledgeSignature.ToJSON(signature)
-	printMsg("client", fmt.Sprintf("Sent signature: %s", zkx_models.ZeroKnowledgeSignature.ToJSON()))
-
-	// Receive token from the server through the clientSocket channel
-	token := <-clientSocket
-	printMsg("client", fmt.Sprintf("Received token: %s", token))
-
-	// Generate a proof using client identity and token
-	proof := zkx_models.ZeroKnowledgeSignature.ToJSON(clientObject.Sign(identity, token))
+	// Send the signature and a challenge for the server through the serverSocket channel
+	signatureJSON, _ := signature.ToJSON()
+	serverSocket <- string(signatureJSON)
+	printMsg("client", fmt.Sprintf("Sent signature: %s", signatureJSON))
+	hello := zkx.NewClientHello()
+	helloJSON, _ := hello.ToJSON()
+	serverSocket <- string(helloJSON)
+
+	// Receive the server hello carrying the token from the server through the clientSocket channel
+	serverHello := zkx_models.ServerHello{}
+	if err := serverHello.FromJSON([]byte(<-clientSocket)); err != nil {
+		panic(err)
+	}
+	printMsg("client", fmt.Sprintf("Received token: %s", serverHello.Token))
+
+	// Check the server against the pinned signature, then generate a proof using client identity and token
+	loginData, err := clientObject.MutualLogin(identity, pinned, *hello, serverHello, audience, time.Minute)
+	if err != nil {
+		printMsg("client", fmt.Sprintf("Server verification failed: %v", err))
+		serverSocket <- ""
+		return
+	}
+	printMsg("client", "Server verification successful")
+	proof, _ := loginData.ToJSON()
 	printMsg("client", fmt.Sprintf("Proof: %s", proof))
 
 	// Send proof to the server through the serverSocket channel
-	serverSocket <- proof
+	serverSocket <- string(proof)
 
 	// Receive result from the server through the clientSocket channel
 	result := <-clientSocket
@@ -48,63 +71,82 @@ func client(clientSocket chan string, serverSocket chan string, wg *sync.WaitGro
 func server(serverSocket chan string, clientSocket chan string, wg *sync.WaitGroup) { // Define a function named server with three parameters
 	defer wg.Done() // Decrement the WaitGroup counter when this function exits
 
-	// Set the server password
-	serverPassword := "SecretServerPassword"
-
 	// Create a ZeroKnowledge object for the server with specified curve and hash algorithm
-	serverZK := zkx.New("Ed25519", "blake2b", nil, "HB2B", 16)
+	serverZK, err := zkx.New("secp256k1", "sha3_256", zkx_types.NewSecretKey([]byte("JWTSecret")), "HS256", 16)
+	if err != nil {
+		panic(err)
+	}
+	nonces := zkx.NewMemoryNonceStore()
 
-	// Receive client signature from the client through the serverSocket channel
-	clientSig := <-serverSocket
-	clientSignature := zkx_models.ZeroKnowledgeSignature.FromJSON(clientSig)
-	printMsg("server", fmt.Sprintf("Received client signature: %s", clientSignature)
+	// Receive client signature and hello from the client through the serverSocket channel
+	clientSignature := zkx_models.ZeroKnowledgeSignature{}
+	if err := clientSignature.FromJSON([]byte(<-serverSocket)); err != nil {
+		panic(err)
+	}
+	printMsg("server", fmt.Sprintf("Received client signature: %x", clientSignature.Signature))
+	hello := zkx_models.ClientHello{}
+	if err := hello.FromJSON([]byte(<-serverSocket)); err != nil {
+		panic(err)
+	}
 
-	// Generate a token signed by the server for the client
-	token := serverZK.Sign(serverPassword, zkx.Token(serverZK))
+	// Generate a token for the client and prove the server identity over its challenge and the token
+	token, err := serverZK.GenerateJWT(clientSignature, time.Minute)
+	if err != nil {
+		panic(err)
+	}
 	printMsg("server", fmt.Sprintf("Generated token: %s", token))
+	serverHello, err := serverZK.NewServerHello(serverPassword, hello, token, audience, nonces, time.Minute)
+	if err != nil {
+		panic(err)
+	}
+	serverHelloJSON, _ := serverHello.ToJSON()
 
-	// Send the token to the client through the clientSocket channel
-	clientSocket <- zkx_models.ZeroKnowledgeData.ToJSON(token)
+	// Send the server hello to the client through the clientSocket channel
+	clientSocket <- string(serverHelloJSON)
 
-	// Receive proof from the client through the serverSocket channel
+	// Receive proof from the client through the serverSocket channel, an empty one means the client aborted
 	proof := <-serverSocket
-	clientProof := zkx_models.ZeroKnowledgeData.FromJSON(proof)
+	if proof == "" {
+		printMsg("server", "Client aborted the login")
+		return
+	}
+	clientProof := zkx_models.ZeroKnowledgeData{}
+	if err := clientProof.FromJSON([]byte(proof)); err != nil {
+		panic(err)
+	}
 	printMsg("server", fmt.Sprintf("Received proof: %s", proof))
 
-	// Verify the received proof
-	tokenData := zkx_models.ZeroKnowledgeData.FromJSON(clientProof.Data)
-	serverVerification := serverZK.Verify(tokenData, clientSignature)
-	printMsg("server", fmt.Sprintf("Server verification result: %t", serverVerification))
-
-	// If server verification fails, notify the client through the clientSocket channel
-	if !serverVerification {
-		clientSocket <- "Server verification failed"
+	// Verify the received proof against the token and the nonce of this login
+	err = serverZK.LoginWithContext(clientProof, audience, nil, nonces)
+	printMsg("server", fmt.Sprintf("Client verification result: %v", err))
+	if err == nil {
+		clientSocket <- "Verification successful"
 	} else {
-		// Otherwise, verify the proof using client signature
-		clientVerification := clientSignature.Verify(clientProof, tokenData)
-		printMsg("server", fmt.Sprintf("Client verification result: %t", clientVerification))
-		if clientVerification {
-			clientSocket <- "Verification successful"
-		} else {
-			clientSocket <- "Verification failed"
-		}
+		clientSocket <- "Verification failed"
 	}
 }
 
 func main() { // Entry point of the program
 	clientSocket := make(chan string) // Create a unbuffered channel of string type named clientSocket
 	serverSocket := make(chan string) // Create a unbuffered channel of string type named serverSocket
-	var wg sync.WaitGroup // Declare a WaitGroup variable named wg
-	wg.Add(2) // Increment the WaitGroup counter by 2
+	var wg sync.WaitGroup             // Declare a WaitGroup variable named wg
+	wg.Add(2)                         // Increment the WaitGroup counter by 2
+
+	// The client pins the server signature it registered with beforehand
+	registrar, err := zkx.New("secp256k1", "sha3_256", nil, "HS256", 16)
+	if err != nil {
+		panic(err)
+	}
+	pinned := registrar.CreateSignature(serverPassword)
 
 	go func() { // Start a new goroutine
 		defer close(clientSocket) // Close the clientSocket channel when this goroutine finishes
 		defer close(serverSocket) // Close the serverSocket channel when this goroutine finishes
-		wg.Wait() // Wait until the WaitGroup counter becomes zero
+		wg.Wait()                 // Wait until the WaitGroup counter becomes zero
 	}()
 
-	go client(clientSocket, serverSocket, &wg) // Start a new goroutine for the client function
-	go server(serverSocket, clientSocket, &wg) // Start a new goroutine for the server function
+	go client(clientSocket, serverSocket, pinned, &wg) // Start a new goroutine for the client function
+	go server(serverSocket, clientSocket, &wg)         // Start a new goroutine for the server function
 
 	wg.Wait() // Wait until all goroutines are finished
 }
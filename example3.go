@@ -1,69 +1,110 @@
 package main // Declares that this file is part of the main package
 
 import (
-	"fmt"  // Import the "fmt" package for formatted I/O
-	"sync" // Import the "sync" package for synchronization primitives
-	HMAC_env "tmp/src/HMAC/core"
-	seed_env "tmp/src/SeedGeneration/core"
+	"crypto/ecdh"                             // Import the "crypto/ecdh" package for the seed key exchange
+	"crypto/rand"                             // Import the "crypto/rand" package for ephemeral exchange keys
+	"fmt"                                     // Import the "fmt" package for formatted I/O
+	"sync"                                    // Import the "sync" package for synchronization primitives
+	"time"                                    // Import the "time" package for proof lifetimes
+	HMAC_env "tmp/src/HMAC/core"              // Import the HMAC core package and alias it as "HMAC_env"
+	secret_types "tmp/src/Secret/types"       // Import the shared SecretKey type and alias it as "secret_types"
+	seed_env "tmp/src/SeedGeneration/core"    // Import the SeedGeneration core package and alias it as "seed_env"
 	zkx "tmp/src/ZeroKnowledge/core"          // Import the ZeroKnowledge core package and alias it as "zkx"
 	zkx_models "tmp/src/ZeroKnowledge/models" // Import the ZeroKnowledge models package and alias it as "zkx_models"
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import the ZeroKnowledge types package and alias it as "zkx_types"
 )
 
 var DEBUG = true // Define a global variable DEBUG and set it to true
 
+const audience = "example3" // Audience the login proof is created for
+
 func printMsg(who string, message string) { // Define a function named printMsg that takes two string parameters
-	if DEBUG {                              // If DEBUG is true, execute the following block
+	if DEBUG { // If DEBUG is true, execute the following block
 		fmt.Printf("[%s] %s\n", who, message) // Print a formatted message to standard output
 	}
 }
 
+const seedPhrase = "jack" // Phrase both ends mix into the main seed
+
+func deriveSeed(exchangeKey *ecdh.PrivateKey, peer string) *secret_types.SecretKey { // Derive the main seed from the X25519 key exchange
+	peerKey, err := ecdh.X25519().NewPublicKey([]byte(peer)) // Parse the public key of the other end
+	if err != nil {
+		panic(err)
+	}
+	shared, err := exchangeKey.ECDH(peerKey) // Compute the shared secret
+	if err != nil {
+		panic(err)
+	}
+	secret := secret_types.NewSecretKey(shared) // Wrap the shared secret so it stays out of logs
+	defer secret.Destroy()                      // Wipe the shared secret once the seed is derived
+	for i := range shared {
+		shared[i] = 0 // Wipe the unwrapped copy
+	}
+	return seed_env.NewSeedGenerator(seedPhrase).Derive(secret)
+}
+
+func newExchangeKey() *ecdh.PrivateKey { // Generate an ephemeral X25519 key for the seed exchange
+	exchangeKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return exchangeKey
+}
+
 func client(clientSocket chan string, serverSocket chan string, wg *sync.WaitGroup) { // Define a function named client with three parameters
 	defer wg.Done() // Decrement the WaitGroup counter when this function exits
 
 	// Create a ZeroKnowledge object for the client with specified curve and hash algorithm
-	clientObject := zkx.New("Ed25519", "blake2b", nil, "HB2B", 16)
+	clientObject, err := zkx.New("secp256k1", "sha3_256", nil, "HS256", 16)
+	if err != nil {
+		panic(err)
+	}
 
 	// Generate a signature for the client identity
-	identity := "John"
+	identity := zkx_types.NewSecretKey([]byte("John"))
 	signature := clientObject.CreateSignature(identity)
 
 	// Send the signature to the server through the serverSocket channel
-	serverSocket <- zkx_models.ZeroKnowledgeSignature.ToJSON(signature)
-	printMsg("client", fmt.Sprintf("Sent signature: %s", zkx_models.ZeroKnowledgeSignature.ToJSON()))
-
-	// Receive token from the server through the clientSocket channel
-	token := <-clientSocket
-	printMsg("client", fmt.Sprintf("Received token: %s", token))
-
-	// Generate a proof using client identity and token
-	proof := zkx_models.ZeroKnowledgeSignature.ToJSON(clientObject.Sign(identity, token))
+	signatureJSON, _ := signature.ToJSON()
+	serverSocket <- string(signatureJSON)
+	printMsg("client", fmt.Sprintf("Sent signature: %s", signatureJSON))
+
+	// Receive the nonce of the server through the clientSocket channel
+	nonce := <-clientSocket
+	printMsg("client", fmt.Sprintf("Received nonce: %x", nonce))
+
+	// Derive the main seed first, so that the login proof can commit to the HMAC session
+	exchangeKey := newExchangeKey()                         // Generate an ephemeral key for the exchange
+	serverSocket <- string(exchangeKey.PublicKey().Bytes()) // Send the public key to the serverSocket channel
+	mainSeed := deriveSeed(exchangeKey, <-clientSocket)     // Derive the main seed with the public key of the server
+	obj := HMAC_env.NewHMACClient("sha256", mainSeed, 1)    // Create a new HMACClient object with SHA-256 hash algorithm, main seed, and iteration count
+	obj.InitDecryptDict()                                   // Initialize the decryption dictionary for the HMACClient
+
+	// Generate a proof bound to the server nonce and to the fingerprint of the HMAC session
+	context := zkx.NewProofContext(audience, []byte(nonce), obj.Fingerprint(), time.Minute)
+	proof, _ := clientObject.SignWithContext(identity, "login", context).ToJSON()
 	printMsg("client", fmt.Sprintf("Proof: %s", proof))
 
-	// Send proof to the server through the serverSocket channel
-	serverSocket <- proof
+	// Send the proof to the server through the serverSocket channel
+	serverSocket <- string(proof)
 
 	// Receive result from the server through the clientSocket channel
 	result := <-clientSocket
 	printMsg("client", fmt.Sprintf("Result: %s", result))
-	if result {
-		mainSeed := seed_env.NewSeedGenerator("jack").Generate() // Generate a main seed using the SeedGenerator with a specified username
-		obj := HMAC_env.NewHMACClient("sha256", mainSeed, 1)     // Create a new HMACClient object with SHA-256 hash algorithm, main seed, and iteration count
-		obj.InitDecryptDict()                                    // Initialize the decryption dictionary for the HMACClient
-
-		// Sending the main seed to the server through the serverSocket channel
-		serverSocket <- string(mainSeed) // Convert the main seed to a string and send it to the serverSocket channel
-
-		// Checking if the server has successfully received the seed
-		if <-clientSocket == obj.EncryptMessage("") { // Wait for a response from the server via the clientSocket channel and compare it with an encrypted empty message
-			// If successful, send a message to the server
-			message := "hello"                                                 // Define a message to send to the server
-			serverSocket <- obj.EncryptMessageByChunks(message)                // Encrypt and send the message to the server through the serverSocket channel
-			printMsg("client", fmt.Sprintf("client sent message %s", message)) // Print a message indicating that the client has sent a message
-
-			// Checking if the server has successfully decrypted the message
-			if <-clientSocket == obj.EncryptMessage(message) { // Wait for a response from the server via the clientSocket channel and compare it with the encrypted message
-				printMsg("client", "server has decrypt message") // If the message matches, print a message indicating that the server has decrypted the message
-			}
+	if result != "Verification successful" {
+		return
+	}
+
+	// Checking if the server has successfully received the seed
+	if <-clientSocket == obj.EncryptMessage("") { // Wait for a response from the server via the clientSocket channel and compare it with an encrypted empty message
+		// If successful, send a message to the server
+		message := "hello"                                                 // Define a message to send to the server
+		serverSocket <- obj.EncryptMessageByChunks(message)                // Encrypt and send the message to the server through the serverSocket channel
+		printMsg("client", fmt.Sprintf("client sent message %s", message)) // Print a message indicating that the client has sent a message
+
+		// Checking if the server has successfully decrypted the message
+		if <-clientSocket == obj.EncryptMessage(message) { // Wait for a response from the server via the clientSocket channel and compare it with the encrypted message
+			printMsg("client", "server has decrypt message") // If the message matches, print a message indicating that the server has decrypted the message
 		}
 	}
 }
@@ -71,67 +112,65 @@ func client(clientSocket chan string, serverSocket chan string, wg *sync.WaitGro
 func server(serverSocket chan string, clientSocket chan string, wg *sync.WaitGroup) { // Define a function named server with three parameters
 	defer wg.Done() // Decrement the WaitGroup counter when this function exits
 
-	// Set the server password
-	serverPassword := "SecretServerPassword"
-
 	// Create a ZeroKnowledge object for the server with specified curve and hash algorithm
-	serverZK := zkx.New("Ed25519", "blake2b", nil, "HB2B", 16)
+	serverZK, err := zkx.New("secp256k1", "sha3_256", nil, "HS256", 16)
+	if err != nil {
+		panic(err)
+	}
+	nonces := zkx.NewMemoryNonceStore()
 
 	// Receive client signature from the client through the serverSocket channel
-	clientSig := <-serverSocket
-	clientSignature := zkx_models.ZeroKnowledgeSignature.FromJSON(clientSig)
-	printMsg("server", fmt.Sprintf("Received client signature: %s", clientSignature)
-
-	// Generate a token signed by the server for the client
-	token := serverZK.Sign(serverPassword, zkx.Token(serverZK))
-	printMsg("server", fmt.Sprintf("Generated token: %s", token))
-
-	// Send the token to the client through the clientSocket channel
-	clientSocket <- zkx_models.ZeroKnowledgeData.ToJSON(token)
-
-	// Receive proof from the client through the serverSocket channel
-	proof := <-serverSocket
-	clientProof := zkx_models.ZeroKnowledgeData.FromJSON(proof)
-	printMsg("server", fmt.Sprintf("Received proof: %s", proof))
-
-	// Verify the received proof
-	tokenData := zkx_models.ZeroKnowledgeData.FromJSON(clientProof.Data)
-	serverVerification := serverZK.Verify(tokenData, clientSignature)
-	printMsg("server", fmt.Sprintf("Server verification result: %t", serverVerification))
-
-	// If server verification fails, notify the client through the clientSocket channel
-	if !serverVerification {
-		clientSocket <- "Server verification failed"
-	} else {
-		// Otherwise, verify the proof using client signature
-		clientVerification := clientSignature.Verify(clientProof, tokenData)
-		printMsg("server", fmt.Sprintf("Client verification result: %t", clientVerification))
-		if clientVerification {
-			clientSocket <- "Verification successful"
-			mainSeed := <-serverSocket // Receive the main seed from the client via the serverSocket channel
-
-			// Create a new HMACClient object with SHA-256 hash algorithm, received main seed, and iteration count
-			obj := HMAC_env.NewHMACClient("sha256", []byte(mainSeed), 1)
-			obj.InitDecryptDict() // Initialize the decryption dictionary for the HMACClient
-
-			// Sending an empty message to the client as acknowledgment
-			clientSocket <- obj.EncryptMessage("") // Encrypt and send an empty message to the client through the clientSocket channel
-
-			// Receiving the message from the client through the serverSocket channel
-			client_mes := <-serverSocket                                    // Receive the encrypted message from the client via the serverSocket channel
-			printMsg("server", fmt.Sprintf("msg encrypted %s", client_mes)) // Print a message indicating the encrypted message received
-
-			// Decrypt the received message by chunks
-			msg := obj.DecryptMessageByChunks(client_mes)            // Decrypt the received message by chunks
-			printMsg("server", fmt.Sprintf("msg decrypted %s", msg)) // Print a message indicating the decrypted message
-
-			// Sending the decrypted message back to the client through the clientSocket channel
-			clientSocket <- obj.EncryptMessageByChunks(msg) // Encrypt and send the decrypted message back to the client
-
-		} else {
-			clientSocket <- "Verification failed"
-		}
+	clientSignature := zkx_models.ZeroKnowledgeSignature{}
+	if err := clientSignature.FromJSON([]byte(<-serverSocket)); err != nil {
+		panic(err)
+	}
+	printMsg("server", fmt.Sprintf("Received client signature: %x", clientSignature.Signature))
+
+	// Issue a nonce for the client and send it through the clientSocket channel
+	nonce, err := nonces.Issue(time.Now().Add(time.Minute))
+	if err != nil {
+		panic(err)
 	}
+	clientSocket <- string(nonce)
+
+	// Exchange public keys with the client, so that both ends derive the main seed without sending it
+	clientKey := <-serverSocket
+	exchangeKey := newExchangeKey()
+	clientSocket <- string(exchangeKey.PublicKey().Bytes())
+	mainSeed := deriveSeed(exchangeKey, clientKey)
+
+	// Create a new HMACClient object with SHA-256 hash algorithm, derived main seed, and iteration count
+	obj := HMAC_env.NewHMACClient("sha256", mainSeed, 1)
+
+	// Receive the proof from the client through the serverSocket channel
+	clientProof := zkx_models.ZeroKnowledgeData{}
+	if err := clientProof.FromJSON([]byte(<-serverSocket)); err != nil {
+		panic(err)
+	}
+
+	// Verify the proof, refusing it unless it commits to the HMAC session
+	err = serverZK.VerifyContext(clientProof, clientSignature, audience, obj.Fingerprint(), nonces)
+	printMsg("server", fmt.Sprintf("Client verification result: %v", err))
+	if err != nil {
+		clientSocket <- "Verification failed"
+		return
+	}
+	clientSocket <- "Verification successful"
+	obj.InitDecryptDict() // Initialize the decryption dictionary for the HMACClient
+
+	// Sending an empty message to the client as acknowledgment
+	clientSocket <- obj.EncryptMessage("") // Encrypt and send an empty message to the client through the clientSocket channel
+
+	// Receiving the message from the client through the serverSocket channel
+	client_mes := <-serverSocket                                    // Receive the encrypted message from the client via the serverSocket channel
+	printMsg("server", fmt.Sprintf("msg encrypted %s", client_mes)) // Print a message indicating the encrypted message received
+
+	// Decrypt the received message by chunks
+	msg := obj.DecryptMessageByChunks(client_mes)            // Decrypt the received message by chunks
+	printMsg("server", fmt.Sprintf("msg decrypted %s", msg)) // Print a message indicating the decrypted message
+
+	// Sending the decrypted message back to the client through the clientSocket channel
+	clientSocket <- obj.EncryptMessageByChunks(msg) // Encrypt and send the decrypted message back to the client
 }
 
 func main() { // Entry point of the program
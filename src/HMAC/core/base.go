@@ -1,21 +1,23 @@
 package core
 
 import (
-	"crypto/sha256"           // Import package to use SHA-256 hash function
-	"encoding/hex"            // Import package for hexadecimal encoding
-	"tmp/src/HMAC/algorithms" // Import package for algorithm definitions
+	HMAC "crypto/hmac"                  // Import package for HMAC construction
+	"crypto/sha256"                     // Import package to use SHA-256 hash function
+	"encoding/hex"                      // Import package for hexadecimal encoding
+	"tmp/src/HMAC/algorithms"           // Import package for algorithm definitions
+	secret_types "tmp/src/Secret/types" // Import package for the SecretKey type
 )
 
 // HMACClient represents HMAC (Hash-based Message Authentication Code) client for message encryption and decryption.
 type HMACClient struct {
-	Algorithm   string            // Algorithm specifies the HMAC algorithm being used
-	Secret      []byte            // Secret stores the secret key used for HMAC
-	SymbolCount int               // SymbolCount specifies the number of symbols per chunk
-	DecryptDict map[string]string // DecryptDict stores precomputed HMAC hashes for decryption
+	Algorithm   string                  // Algorithm specifies the HMAC algorithm being used
+	Secret      *secret_types.SecretKey // Secret stores the secret key used for HMAC
+	SymbolCount int                     // SymbolCount specifies the number of symbols per chunk
+	DecryptDict map[string]string       // DecryptDict stores precomputed HMAC hashes for decryption
 }
 
 // NewHMACClient creates a new instance of HMACClient with the given parameters.
-func NewHMACClient(algorithm string, secret []byte, symbolCount int) *HMACClient {
+func NewHMACClient(algorithm string, secret *secret_types.SecretKey, symbolCount int) *HMACClient {
 	return &HMACClient{
 		Algorithm:   algorithm,
 		Secret:      secret,
@@ -74,9 +76,9 @@ func (h *HMACClient) EncryptMessageByChunks(message string) string {
 
 // EncryptMessage encrypts a message using HMAC.
 func (h *HMACClient) EncryptMessage(message string) string {
-	hash := HMAC.New(sha256.New, h.Secret)   // Create new HMAC hash using SHA-256 and secret key
-	hash.Write([]byte(message))              // Write message to hash
-	return hex.EncodeToString(hash.Sum(nil)) // Return hexadecimal encoded hash
+	hash := HMAC.New(sha256.New, h.Secret.Bytes()) // Create new HMAC hash using SHA-256 and secret key
+	hash.Write([]byte(message))                    // Write message to hash
+	return hex.EncodeToString(hash.Sum(nil))       // Return hexadecimal encoded hash
 }
 
 // DecryptMessageByChunks decrypts a message by dividing it into chunks and decrypting each chunk.
@@ -104,3 +106,10 @@ func (h *HMACClient) DecryptMessage(message string) string {
 	}
 	panic("The algorithm is invalid")
 }
+
+// Fingerprint derives a public fingerprint of the session key that a login proof can commit to.
+func (h *HMACClient) Fingerprint() []byte {
+	hash := HMAC.New(sha256.New, h.Secret.Bytes()) // Key the hash so the fingerprint reveals nothing about the secret
+	hash.Write([]byte("HMAC/ChannelBinding/" + h.Algorithm))
+	return hash.Sum(nil)
+}
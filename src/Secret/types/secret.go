@@ -0,0 +1,68 @@
+package types
+
+import (
+	"crypto/subtle" // Import package for constant-time comparison
+	"log/slog"      // Import package for structured logging
+)
+
+// redacted is printed wherever a secret would otherwise appear
+const redacted = "SecretKey(REDACTED)"
+
+// SecretKey holds secret bytes that never show up in logs or JSON and can be wiped after use
+type SecretKey struct {
+	key []byte // Secret bytes, owned by the SecretKey
+}
+
+// NewSecretKey creates a SecretKey holding a copy of the given bytes
+func NewSecretKey(key []byte) *SecretKey {
+	return &SecretKey{key: append([]byte(nil), key...)} // Copy so the caller can wipe its own slice
+}
+
+// Bytes returns the secret bytes, callers must not keep them beyond the life of the SecretKey
+func (s *SecretKey) Bytes() []byte {
+	if s == nil {
+		return nil
+	}
+	return s.key
+}
+
+// Len returns the number of secret bytes
+func (s *SecretKey) Len() int {
+	return len(s.Bytes())
+}
+
+// Equal compares two secrets in constant time
+func (s *SecretKey) Equal(other *SecretKey) bool {
+	return subtle.ConstantTimeCompare(s.Bytes(), other.Bytes()) == 1
+}
+
+// Destroy overwrites the secret bytes with zeros and forgets them
+func (s *SecretKey) Destroy() {
+	if s == nil {
+		return
+	}
+	for i := range s.key {
+		s.key[i] = 0
+	}
+	s.key = nil
+}
+
+// String redacts the secret for the %v and %s verbs
+func (s SecretKey) String() string {
+	return redacted
+}
+
+// GoString redacts the secret for the %#v verb
+func (s SecretKey) GoString() string {
+	return redacted
+}
+
+// MarshalJSON redacts the secret when it is part of a JSON document
+func (s SecretKey) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+// LogValue redacts the secret in structured logs
+func (s SecretKey) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
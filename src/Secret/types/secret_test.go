@@ -0,0 +1,67 @@
+package types
+
+import (
+	"bytes"         // Import package for byte slice comparison
+	"encoding/json" // Import package for JSON encoding
+	"fmt"           // Import package for formatted output
+	"log/slog"      // Import package for structured logging
+	"strings"       // Import package for string searching
+	"testing"       // Import package for testing
+)
+
+func TestSecretKeyIsRedacted(t *testing.T) {
+	secret := NewSecretKey([]byte("hunter2"))
+	wrapper := struct {
+		Name   string
+		Secret *SecretKey
+	}{"alice", secret}
+
+	outputs := []string{
+		fmt.Sprint(secret),
+		fmt.Sprintf("%v %s %+v %#v", *secret, *secret, wrapper, *secret),
+	}
+	encoded, err := json.Marshal(wrapper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs = append(outputs, string(encoded))
+	logged := &bytes.Buffer{}
+	slog.New(slog.NewTextHandler(logged, nil)).Info("login", "secret", *secret)
+	outputs = append(outputs, logged.String())
+
+	for _, output := range outputs {
+		if strings.Contains(output, "hunter2") {
+			t.Fatalf("secret leaked in %q", output)
+		}
+		if !strings.Contains(output, redacted) {
+			t.Fatalf("secret not redacted in %q", output)
+		}
+	}
+}
+
+func TestSecretKeyCopiesAndDestroys(t *testing.T) {
+	key := []byte("hunter2")
+	secret := NewSecretKey(key)
+	key[0] = 'X'
+	if string(secret.Bytes()) != "hunter2" {
+		t.Fatal("secret shares its bytes with the caller")
+	}
+	if !secret.Equal(NewSecretKey([]byte("hunter2"))) || secret.Equal(NewSecretKey([]byte("hunter3"))) {
+		t.Fatal("secrets compare wrongly")
+	}
+
+	held := secret.Bytes()
+	secret.Destroy()
+	if !bytes.Equal(held, make([]byte, len(held))) {
+		t.Fatal("destroyed secret was not wiped")
+	}
+	if secret.Len() != 0 || secret.Bytes() != nil {
+		t.Fatal("destroyed secret still holds bytes")
+	}
+	secret.Destroy()
+	var missing *SecretKey
+	missing.Destroy()
+	if missing.Len() != 0 {
+		t.Fatal("nil secret has bytes")
+	}
+}
@@ -1,7 +1,8 @@
 package core
 
 import (
-	"tmp/src/SeedGeneration/utils" // Import the utils package for utility functions
+	secret_types "tmp/src/Secret/types" // Import the SecretKey type
+	"tmp/src/SeedGeneration/utils"      // Import the utils package for utility functions
 )
 
 // SeedGenerator represents a seed generator.
@@ -15,9 +16,28 @@ func NewSeedGenerator(phrase string) *SeedGenerator {
 }
 
 // Generate generates a random seed.
-func (sg *SeedGenerator) Generate() []byte {
+func (sg *SeedGenerator) Generate() *secret_types.SecretKey {
 	length := utils.GetRandomInt()                             // Get a random length for the seed
 	randomBytes := make([]byte, length)                        // Generate random bytes of the specified length
 	combinedBytes := append(randomBytes, []byte(sg.phrase)...) // Combine random bytes with the phrase
-	return utils.HashDigest(combinedBytes)                     // Hash the combined bytes and return the result
+	digest := utils.HashDigest(combinedBytes)                  // Hash the combined bytes
+	seed := secret_types.NewSecretKey(digest)                  // Wrap the digest so it stays out of logs
+	for i := range digest {
+		digest[i] = 0 // Wipe the unwrapped copy
+	}
+	return seed
+}
+
+// Derive derives a seed from the secret shared by both ends of a key exchange, so the seed never travels.
+func (sg *SeedGenerator) Derive(shared *secret_types.SecretKey) *secret_types.SecretKey {
+	combinedBytes := append(append([]byte(nil), shared.Bytes()...), []byte(sg.phrase)...) // Combine the shared secret with the phrase
+	digest := utils.HashDigest(combinedBytes)                                             // Hash the combined bytes
+	seed := secret_types.NewSecretKey(digest)                                             // Wrap the digest so it stays out of logs
+	for i := range combinedBytes {
+		combinedBytes[i] = 0 // Wipe the copy of the shared secret
+	}
+	for i := range digest {
+		digest[i] = 0 // Wipe the unwrapped copy
+	}
+	return seed
 }
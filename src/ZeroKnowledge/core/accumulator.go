@@ -0,0 +1,396 @@
+package core
+
+import (
+	"bytes"                                   // Import package for byte slice comparison
+	"encoding/json"                           // Import package for JSON encoding and decoding
+	"errors"                                  // Import package for error handling
+	"fmt"                                     // Import package for formatted I/O
+	"math/big"                                // Import package for big integer arithmetic
+	"sort"                                    // Import package for sorting
+	"sync"                                    // Import package for synchronization primitives
+	"time"                                    // Import package for handling time
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+)
+
+// The accumulator is keyed: with the issuer key a, a member holding element y and witness C satisfies
+// (a + y)*C = V. Adding a member leaves V unchanged, revoking y publishes V' = V/(a + y) together with
+// y, and every other member updates its witness to (C - V')/(y' - y) without any secret. Without a
+// pairing only the issuer can check the relation, so non-revocation proofs are verified by the issuer:
+// the member shows B = r*C and D = r*V - y*B and proves knowledge of r and y, the issuer checks
+// that D = a*B. Blinding with r makes proofs of the same member unlinkable.
+//
+// The element is tied to the login identity of the member: at enrolment the issuer publishes the
+// binding K = X + y*H of the member signature X = x*G. One relation proves D = r*V - y*B and K = x*G + y*H
+// with the same y, so a witness is useless without the secret of the member it was issued to. The
+// proof names a group of current bindings and hides which one is the member's.
+
+// Witness names of the non-revocation relation, distinct from the names of any login statement
+const (
+	accumulatorBlind   = "accumulator/blind"   // Blinding factor r
+	accumulatorElement = "accumulator/element" // Element y of the member
+	accumulatorKey     = "accumulator/key"     // Login identity x of the member
+)
+
+// accumulatorBinding labels the base H that binds elements to login identities
+const accumulatorBinding = "Accumulator/Binding"
+
+// accumulatorMember is what the issuer keeps for an enrolled member
+type accumulatorMember struct {
+	element *zkx_types.Scalar // Element y
+	binding []byte            // Marshalled binding X + y*H
+}
+
+// Accumulator is the issuer side of the revocation accumulator
+type Accumulator struct {
+	ZK      *ZeroKnowledge                // Instance the accumulator lives on
+	Signer  LoginSigner                   // Key the epochs are signed with
+	key     *zkx_types.Scalar             // Secret key a
+	mu      sync.Mutex                    // Guards the fields below
+	members map[string]accumulatorMember  // Every member that is not revoked
+	epochs  []zkx_models.AccumulatorEpoch // Every published epoch, the last one is current
+}
+
+// NewAccumulator creates an empty accumulator and publishes its first epoch
+func NewAccumulator(z *ZeroKnowledge, signer LoginSigner) (*Accumulator, error) {
+	key, err := z.randomSecretScalar()
+	if err != nil {
+		return nil, err
+	}
+	start, err := z.randomSecretScalar()
+	if err != nil {
+		return nil, err
+	}
+	defer start.Destroy()
+	a := &Accumulator{ZK: z, Signer: signer, key: key, members: make(map[string]accumulatorMember)}
+	if err := a.publish(z.secretBaseMult(start), nil); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Epoch returns the current epoch
+func (a *Accumulator) Epoch() zkx_models.AccumulatorEpoch {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.epochs[len(a.epochs)-1]
+}
+
+// Epochs returns the epochs published after the given one, members update their witnesses with them
+func (a *Accumulator) Epochs(since uint64) []zkx_models.AccumulatorEpoch {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if since >= uint64(len(a.epochs)) {
+		return nil
+	}
+	return append([]zkx_models.AccumulatorEpoch(nil), a.epochs[since+1:]...)
+}
+
+// Bindings returns the bindings of the current members, provers choose the group they hide in from them
+func (a *Accumulator) Bindings() [][]byte {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	bindings := make([][]byte, 0, len(a.members))
+	for _, member := range a.members {
+		bindings = append(bindings, member.binding)
+	}
+	sort.Slice(bindings, func(i, j int) bool { return bytes.Compare(bindings[i], bindings[j]) < 0 })
+	return bindings
+}
+
+// Add enrolls a member under its login signature and returns its witness for the current epoch, hand it to the member only
+func (a *Accumulator) Add(member string, signature zkx_models.ZeroKnowledgeSignature) (*zkx_models.MembershipWitness, error) {
+	if signature.Service != "" {
+		return nil, errors.New("Members must be enrolled with their master signature")
+	}
+	identity, err := a.ZK.unmarshalPoint(signature.Signature)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.members[member]; ok {
+		return nil, errors.New("Member is already enrolled")
+	}
+	element, err := a.ZK.randomSecretScalar()
+	if err != nil {
+		return nil, err
+	}
+	sum := new(zkx_types.Scalar).Add(a.key, element)
+	if sum.IsZero() {
+		return nil, errors.New("Element collides with the accumulator key, try again")
+	}
+	current := a.epochs[len(a.epochs)-1]
+	value, _ := a.ZK.unmarshalPoint(current.Value)
+
+	// C = V/(a + y)
+	witness := a.ZK.secretMult(value, new(zkx_types.Scalar).Invert(sum))
+
+	// K = X + y*H
+	binding := a.ZK.marshalPoint(a.ZK.addPoints(identity, a.ZK.secretMult(a.ZK.HashBase(accumulatorBinding), element)))
+	a.members[member] = accumulatorMember{element: element, binding: binding}
+	return &zkx_models.MembershipWitness{
+		Epoch:   current.Epoch,
+		Element: element.Bytes(),
+		Binding: binding,
+		Witness: a.ZK.marshalPoint(witness),
+	}, nil
+}
+
+// Revoke removes a member and publishes the next epoch
+func (a *Accumulator) Revoke(member string) (*zkx_models.AccumulatorEpoch, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	enrolled, ok := a.members[member]
+	if !ok {
+		return nil, errors.New("Unknown member")
+	}
+	element := enrolled.element
+	value, _ := a.ZK.unmarshalPoint(a.epochs[len(a.epochs)-1].Value)
+
+	// V' = V/(a + y)
+	next := a.ZK.secretMult(value, new(zkx_types.Scalar).Invert(new(zkx_types.Scalar).Add(a.key, element)))
+	if err := a.publish(next, element.Bytes()); err != nil {
+		return nil, err
+	}
+	delete(a.members, member)
+	epoch := a.epochs[len(a.epochs)-1]
+	return &epoch, nil
+}
+
+// publish signs and appends the epoch with the value, callers must hold the lock
+func (a *Accumulator) publish(value zkx_models.Point, revoked []byte) error {
+	epoch := zkx_models.AccumulatorEpoch{
+		Params:  a.ZK.Params,
+		Epoch:   uint64(len(a.epochs)),
+		Value:   a.ZK.marshalPoint(value),
+		Revoked: revoked,
+		Issued:  time.Now().UTC(),
+		Issuer:  a.Signer.Signature(),
+	}
+	signed, err := a.Signer.Sign(epochContent(epoch))
+	if err != nil {
+		return err
+	}
+	epoch.Proof = signed.Proof
+	a.epochs = append(a.epochs, epoch)
+	return nil
+}
+
+// VerifyEpoch checks that the issuer published the epoch
+func (z *ZeroKnowledge) VerifyEpoch(epoch zkx_models.AccumulatorEpoch, issuer zkx_models.ZeroKnowledgeSignature) bool {
+	return sameSignature(epoch.Issuer, issuer) && z.Verify(epoch.Proof, issuer, epochContent(epoch))
+}
+
+// UpdateWitness carries a witness through the epochs that followed it, they must be given in order
+func (z *ZeroKnowledge) UpdateWitness(witness zkx_models.MembershipWitness, issuer zkx_models.ZeroKnowledgeSignature, epochs []zkx_models.AccumulatorEpoch) (*zkx_models.MembershipWitness, error) {
+	element, err := z.witnessElement(witness)
+	if err != nil {
+		return nil, err
+	}
+	defer element.Destroy()
+	current, err := z.unmarshalPoint(witness.Witness)
+	if err != nil {
+		return nil, err
+	}
+	epochNumber := witness.Epoch
+	for _, epoch := range epochs {
+		if epoch.Epoch != epochNumber+1 || !z.VerifyEpoch(epoch, issuer) {
+			return nil, errors.New("Accumulator epochs are not consecutive or not signed by the issuer")
+		}
+		revoked, err := zkx_types.NewScalar(z.scalarOrder(), epoch.Revoked)
+		if err != nil {
+			return nil, err
+		}
+		value, err := z.unmarshalPoint(epoch.Value)
+		if err != nil {
+			return nil, err
+		}
+		difference := new(zkx_types.Scalar).Sub(revoked, element)
+		if difference.IsZero() {
+			return nil, zkx_errors.ErrMembershipRevoked
+		}
+
+		// C' = (C - V')/(y' - y)
+		current = z.secretMult(z.subPoints(current, value), new(zkx_types.Scalar).Invert(difference))
+		epochNumber = epoch.Epoch
+	}
+	return &zkx_models.MembershipWitness{
+		Epoch:   epochNumber,
+		Element: append([]byte(nil), witness.Element...),
+		Binding: append([]byte(nil), witness.Binding...),
+		Witness: z.marshalPoint(current),
+	}, nil
+}
+
+// ProveNonRevocation proves over the data that the owner of the secret holds an unrevoked witness, hidden
+// among the group of bindings, which must contain its own; a nil group stands for the witness binding only
+func (z *ZeroKnowledge) ProveNonRevocation(epoch zkx_models.AccumulatorEpoch, witness zkx_models.MembershipWitness, secret *zkx_types.SecretKey, group [][]byte, data interface{}) (*zkx_models.NonRevocationProof, error) {
+	return z.ProveNonRevocationWith(epoch, witness, secret, group, nil, nil, data)
+}
+
+// ProveNonRevocationWith proves non-revocation and another statement, such as a credential or a login, in one
+// Fiat-Shamir transcript so that neither proof can be replayed with another
+func (z *ZeroKnowledge) ProveNonRevocationWith(epoch zkx_models.AccumulatorEpoch, witness zkx_models.MembershipWitness, secret *zkx_types.SecretKey, group [][]byte, login *zkx_models.SigmaStatement, witnesses map[string]*zkx_types.Scalar, data interface{}) (*zkx_models.NonRevocationProof, error) {
+	if witness.Epoch != epoch.Epoch {
+		return nil, zkx_errors.ErrStaleEpoch
+	}
+	if group == nil {
+		group = [][]byte{witness.Binding}
+	}
+	if !containsBytes(group, witness.Binding) {
+		return nil, errors.New("Group does not contain the binding of the witness")
+	}
+	element, err := z.witnessElement(witness)
+	if err != nil {
+		return nil, err
+	}
+	defer element.Destroy()
+	value, err := z.unmarshalPoint(epoch.Value)
+	if err != nil {
+		return nil, err
+	}
+	current, err := z.unmarshalPoint(witness.Witness)
+	if err != nil {
+		return nil, err
+	}
+	r, err := z.randomSecretScalar()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Destroy()
+	key := z.secretScalar(secret)
+	defer key.Destroy()
+
+	// B = r*C and D = r*V - y*B
+	blinded := z.secretMult(current, r)
+	complement := z.subPoints(z.secretMult(value, r), z.secretMult(blinded, element))
+	proof := &zkx_models.NonRevocationProof{
+		Epoch:      epoch.Epoch,
+		Group:      group,
+		Blinded:    z.marshalPoint(blinded),
+		Complement: z.marshalPoint(complement),
+	}
+
+	statement, err := z.nonRevocationStatement(value, proof, login)
+	if err != nil {
+		return nil, err
+	}
+	all := map[string]*zkx_types.Scalar{accumulatorBlind: r, accumulatorElement: element, accumulatorKey: key}
+	for name, scalar := range witnesses {
+		all[name] = scalar
+	}
+	sigma, err := z.ProveStatement(statement, all, data)
+	if err != nil {
+		return nil, err
+	}
+	proof.Proof = *sigma
+	return proof, nil
+}
+
+// VerifyNonRevocation checks a non-revocation proof over the data against the current epoch
+func (a *Accumulator) VerifyNonRevocation(proof zkx_models.NonRevocationProof, data interface{}) error {
+	return a.VerifyNonRevocationWith(proof, nil, data)
+}
+
+// VerifyNonRevocationWith checks a proof made with ProveNonRevocationWith and the same statement
+func (a *Accumulator) VerifyNonRevocationWith(proof zkx_models.NonRevocationProof, login *zkx_models.SigmaStatement, data interface{}) error {
+	current := a.Epoch()
+	if proof.Epoch != current.Epoch {
+		return zkx_errors.ErrStaleEpoch
+	}
+	if proof.Proof.Data != fmt.Sprint(data) || len(proof.Group) == 0 {
+		return zkx_errors.ErrInvalidProof
+	}
+
+	// Every binding of the group must belong to a member that is still enrolled
+	bindings := a.Bindings()
+	for _, binding := range proof.Group {
+		if !containsBytes(bindings, binding) {
+			return zkx_errors.ErrMembershipRevoked
+		}
+	}
+	blinded, err := a.ZK.unmarshalPoint(proof.Blinded)
+	if err != nil || (blinded.X.Sign() == 0 && blinded.Y.Sign() == 0) {
+		return zkx_errors.ErrInvalidProof
+	}
+
+	// Only a valid witness gives D = a*B
+	if !bytes.Equal(a.ZK.marshalPoint(a.ZK.secretMult(blinded, a.key)), proof.Complement) {
+		return zkx_errors.ErrMembershipRevoked
+	}
+	value, _ := a.ZK.unmarshalPoint(current.Value)
+	statement, err := a.ZK.nonRevocationStatement(value, &proof, login)
+	if err != nil || !a.ZK.VerifyStatement(statement, proof.Proof) {
+		return zkx_errors.ErrInvalidProof
+	}
+	return nil
+}
+
+// nonRevocationStatement states, for one binding K of the group, D = r*V + y*(-B) and K = x*G + y*H in a
+// single relation, joined with the other statement if there is one
+func (z *ZeroKnowledge) nonRevocationStatement(value zkx_models.Point, proof *zkx_models.NonRevocationProof, login *zkx_models.SigmaStatement) (zkx_models.SigmaStatement, error) {
+	blinded, err := z.unmarshalPoint(proof.Blinded)
+	if err != nil {
+		return zkx_models.SigmaStatement{}, err
+	}
+	complement, err := z.unmarshalPoint(proof.Complement)
+	if err != nil {
+		return zkx_models.SigmaStatement{}, err
+	}
+	negated := zkx_models.Point{X: blinded.X, Y: new(big.Int).Mod(new(big.Int).Neg(blinded.Y), z.Curve.Params().P)}
+	accumulated := z.Equation(complement, z.Term(accumulatorBlind, value), z.Term(accumulatorElement, negated))
+
+	parts := make([]zkx_models.SigmaStatement, len(proof.Group))
+	for i, raw := range proof.Group {
+		if containsBytes(proof.Group[:i], raw) {
+			return zkx_models.SigmaStatement{}, errors.New("Binding appears twice in the group")
+		}
+		binding, err := z.unmarshalPoint(raw)
+		if err != nil {
+			return zkx_models.SigmaStatement{}, err
+		}
+		bound := z.Equation(binding, z.Term(accumulatorKey, z.Generator()), z.Term(accumulatorElement, z.HashBase(accumulatorBinding)))
+		parts[i] = Relation(accumulated, bound)
+	}
+	statement := parts[0]
+	if len(parts) > 1 {
+		statement = Or(parts...)
+	}
+	if login == nil {
+		return statement, nil
+	}
+	return And(statement, *login), nil
+}
+
+// witnessElement decodes the element of a witness, which must be a non-zero scalar below the order
+func (z *ZeroKnowledge) witnessElement(witness zkx_models.MembershipWitness) (*zkx_types.Scalar, error) {
+	if len(witness.Element) == 0 || new(big.Int).SetBytes(witness.Element).Cmp(z.Curve.Params().N) >= 0 {
+		return nil, errors.New("Membership witness has no valid element")
+	}
+	element, err := zkx_types.NewScalar(z.scalarOrder(), witness.Element)
+	if err != nil {
+		return nil, err
+	}
+	if element.IsZero() {
+		return nil, errors.New("Membership witness has no valid element")
+	}
+	return element, nil
+}
+
+// epochContent encodes everything the issuer signs for an epoch
+func epochContent(epoch zkx_models.AccumulatorEpoch) string {
+	content, _ := json.Marshal([]interface{}{"Accumulator/Epoch", epoch.Params, epoch.Epoch, epoch.Value, epoch.Revoked, epoch.Issued.UnixNano()})
+	return string(content)
+}
+
+// containsBytes reports whether the list holds the value
+func containsBytes(list [][]byte, value []byte) bool {
+	for _, item := range list {
+		if bytes.Equal(item, value) {
+			return true
+		}
+	}
+	return false
+}
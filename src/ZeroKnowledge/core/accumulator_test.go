@@ -0,0 +1,160 @@
+package core
+
+import (
+	"errors"                                  // Import package for error handling
+	"testing"                                 // Import package for testing
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+// newTestAccumulator creates an accumulator and enrolls the named members
+func newTestAccumulator(t *testing.T, names ...string) (*ZeroKnowledge, *Accumulator, map[string]zkx_models.MembershipWitness) {
+	t.Helper()
+	z := newTestZK(t)
+	accumulator, err := NewAccumulator(z, NewCurveSigner(z, testSecret("issuer")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	witnesses := make(map[string]zkx_models.MembershipWitness)
+	for _, name := range names {
+		witness, err := accumulator.Add(name, z.CreateSignature(testSecret(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		witnesses[name] = *witness
+	}
+	return z, accumulator, witnesses
+}
+
+func TestNonRevocationRoundTrip(t *testing.T) {
+	z, accumulator, witnesses := newTestAccumulator(t, "alice", "bob", "carol")
+	proof, err := z.ProveNonRevocation(accumulator.Epoch(), witnesses["alice"], testSecret("alice"), accumulator.Bindings(), "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := accumulator.VerifyNonRevocation(*proof, "session"); err != nil {
+		t.Fatal(err)
+	}
+	if err := accumulator.VerifyNonRevocation(*proof, "other session"); !errors.Is(err, zkx_errors.ErrInvalidProof) {
+		t.Fatalf("proof over other data gave %v", err)
+	}
+
+	// Revoking bob moves the epoch on, the others update their witnesses and bob cannot
+	issuer := accumulator.Signer.Signature()
+	if _, err := accumulator.Revoke("bob"); err != nil {
+		t.Fatal(err)
+	}
+	if err := accumulator.VerifyNonRevocation(*proof, "session"); !errors.Is(err, zkx_errors.ErrStaleEpoch) {
+		t.Fatalf("proof for an old epoch gave %v", err)
+	}
+	epochs := accumulator.Epochs(witnesses["alice"].Epoch)
+	updated, err := z.UpdateWitness(witnesses["alice"], issuer, epochs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err = z.ProveNonRevocation(accumulator.Epoch(), *updated, testSecret("alice"), nil, "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := accumulator.VerifyNonRevocation(*proof, "session"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := z.UpdateWitness(witnesses["bob"], issuer, epochs); !errors.Is(err, zkx_errors.ErrMembershipRevoked) {
+		t.Fatalf("update of a revoked witness gave %v", err)
+	}
+
+	// An old witness proves against the new epoch only to be refused
+	stale := witnesses["bob"]
+	stale.Epoch = accumulator.Epoch().Epoch
+	proof, err = z.ProveNonRevocation(accumulator.Epoch(), stale, testSecret("bob"), nil, "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := accumulator.VerifyNonRevocation(*proof, "session"); !errors.Is(err, zkx_errors.ErrMembershipRevoked) {
+		t.Fatalf("revoked member gave %v", err)
+	}
+	if _, err := z.UpdateWitness(witnesses["carol"], z.CreateSignature(testSecret("mallory")), epochs); err == nil {
+		t.Fatal("witness updated with epochs of another issuer")
+	}
+}
+
+// Regression: the element used to marshal to {} and a decoded witness made the prover panic
+func TestMembershipWitnessJSON(t *testing.T) {
+	z, accumulator, witnesses := newTestAccumulator(t, "alice")
+	witness := witnesses["alice"]
+	data, err := witness.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := zkx_models.MembershipWitness{}
+	if err := decoded.FromJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	proof, err := z.ProveNonRevocation(accumulator.Epoch(), decoded, testSecret("alice"), nil, "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := accumulator.VerifyNonRevocation(*proof, "session"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, element := range [][]byte{nil, make([]byte, 32), z.Curve.Params().N.Bytes()} {
+		broken := decoded
+		broken.Element = element
+		if _, err := z.ProveNonRevocation(accumulator.Epoch(), broken, testSecret("alice"), nil, "session"); err == nil {
+			t.Fatalf("proved with the element %x", element)
+		}
+	}
+}
+
+// Regression: the element was not tied to anyone, so a revoked member could prove with any unrevoked witness
+func TestNonRevocationBoundToIdentity(t *testing.T) {
+	z, accumulator, witnesses := newTestAccumulator(t, "alice", "bob", "mallory")
+	issuer := accumulator.Signer.Signature()
+	if _, err := accumulator.Revoke("mallory"); err != nil {
+		t.Fatal(err)
+	}
+	stolen, err := z.UpdateWitness(witnesses["bob"], issuer, accumulator.Epochs(witnesses["bob"].Epoch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	group := accumulator.Bindings()
+	if _, err := z.ProveNonRevocation(accumulator.Epoch(), *stolen, testSecret("mallory"), group, "session"); err == nil {
+		t.Fatal("revoked member proved with the witness of another member")
+	}
+
+	// The stolen element under the binding of the revoked member satisfies neither side
+	rebound := *stolen
+	rebound.Binding = witnesses["mallory"].Binding
+	if _, err := z.ProveNonRevocation(accumulator.Epoch(), rebound, testSecret("mallory"), append(group, rebound.Binding), "session"); err == nil {
+		t.Fatal("revoked member proved under its own binding")
+	}
+
+	// The owner proves within the group, and the group is covered by the proof
+	proof, err := z.ProveNonRevocation(accumulator.Epoch(), *stolen, testSecret("bob"), group, "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := accumulator.VerifyNonRevocation(*proof, "session"); err != nil {
+		t.Fatal(err)
+	}
+	tampered := *proof
+	tampered.Group = [][]byte{group[1], group[0]}
+	if err := accumulator.VerifyNonRevocation(tampered, "session"); !errors.Is(err, zkx_errors.ErrInvalidProof) {
+		t.Fatalf("reordered group gave %v", err)
+	}
+	tampered.Group = [][]byte{group[0], group[0]}
+	if err := accumulator.VerifyNonRevocation(tampered, "session"); !errors.Is(err, zkx_errors.ErrInvalidProof) {
+		t.Fatalf("group with a repeated binding gave %v", err)
+	}
+	tampered.Group = append(append([][]byte(nil), group...), witnesses["mallory"].Binding)
+	if err := accumulator.VerifyNonRevocation(tampered, "session"); !errors.Is(err, zkx_errors.ErrMembershipRevoked) {
+		t.Fatalf("group with a revoked binding gave %v", err)
+	}
+	if _, err := z.ProveNonRevocation(accumulator.Epoch(), *stolen, testSecret("bob"), [][]byte{witnesses["alice"].Binding}, "session"); err == nil {
+		t.Fatal("proved within a group that lacks the own binding")
+	}
+	if _, err := accumulator.Add("eve", z.CreatePseudonym(testSecret("eve"), "service")); err == nil {
+		t.Fatal("enrolled a member with a pseudonym")
+	}
+}
@@ -1,16 +1,18 @@
 package core
 
 import (
-	"crypto/ecdsa"                            // Import ECDSA cryptographic functions
 	"crypto/elliptic"                         // Import elliptic curve functions
 	"crypto/rand"                             // Import cryptographic random number generator
 	"crypto/sha256"                           // Import SHA-256 cryptographic hash function
 	"encoding/json"                           // Import package for JSON encoding and decoding
 	"errors"                                  // Import package for error handling
+	"fmt"                                     // Import package for formatted I/O
 	"github.com/golang-jwt/jwt/v4"            // Import JWT package for JSON Web Tokens
 	"math/big"                                // Import package for big integer arithmetic
+	"sync"                                    // Import package for synchronization primitives
 	"time"                                    // Import package for handling time
 	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
 	zkx_utils "tmp/src/ZeroKnowledge/utils"   // Import Zero Knowledge utility functions
 )
 
@@ -19,13 +21,13 @@ type ZeroKnowledge struct {
 	Params    zkx_models.ZeroKnowledgeParams // Parameters for Zero Knowledge
 	Curve     zkx_models.Curve               // Elliptic curve
 	Bits      int                            // Number of bits
-	Secret    []byte                         // Secret key for JWT
+	Secret    *zkx_types.SecretKey           // Secret key for JWT
 	Algorithm string                         // JWT algorithm
 	Issuer    string                         // Issuer for JWT
 }
 
 // New creates a new instance of ZeroKnowledge
-func New(curveName string, hashAlg string, jwtSecret []byte, jwtAlg string, saltSize int) (*ZeroKnowledge, error) {
+func New(curveName string, hashAlg string, jwtSecret *zkx_types.SecretKey, jwtAlg string, saltSize int) (*ZeroKnowledge, error) {
 	// Get the elliptic curve object
 	curve := zkx_utils.CurveByName(curveName)
 	if curve == nil {
@@ -42,28 +44,41 @@ func New(curveName string, hashAlg string, jwtSecret []byte, jwtAlg string, salt
 	// Create a new instance of ZeroKnowledge
 	zk := ZeroKnowledge{
 		Params:    params,
-		Curve:     curve,
+		Curve:     zkx_models.Curve{Curve: curve},
+		Bits:      curve.Params().BitSize,
 		Secret:    jwtSecret,
 		Algorithm: jwtAlg,
 	}
 
-	return &zk
+	return &zk, nil
 }
 
 // GenerateJWT generates a JSON Web Token (JWT) using the provided signature and expiration time
 func (z *ZeroKnowledge) GenerateJWT(signature zkx_models.ZeroKnowledgeSignature, exp time.Duration) (string, error) {
-	if len(z.Secret) == 0 {
+	return z.GenerateUserJWT("", signature, exp)
+}
+
+// GenerateUserJWT generates a JWT like GenerateJWT with the user as its subject
+func (z *ZeroKnowledge) GenerateUserJWT(user string, signature zkx_models.ZeroKnowledgeSignature, exp time.Duration) (string, error) {
+	if z.Secret.Len() == 0 {
 		return "", errors.New("JWT secret is empty")
 	}
+	signatureJSON, err := signature.ToJSON()
+	if err != nil {
+		return "", err
+	}
 	now := time.Now().UTC()
 	claims := map[string]interface{}{
-		"signature": signature,
-		"iat":       now,
-		"nbf":       now,
-		"exp":       now.Add(exp),
+		"signature": string(signatureJSON),
+		"iat":       jwt.NewNumericDate(now),
+		"nbf":       jwt.NewNumericDate(now),
+		"exp":       jwt.NewNumericDate(now.Add(exp)),
 		"iss":       z.Issuer,
 	}
-	token, err := JwtEncode(claims, z.Secret, z.Algorithm)
+	if user != "" {
+		claims["sub"] = user
+	}
+	token, err := JwtEncode(claims, z.Secret.Bytes(), z.Algorithm)
 	if err != nil {
 		return "", err
 	}
@@ -72,27 +87,33 @@ func (z *ZeroKnowledge) GenerateJWT(signature zkx_models.ZeroKnowledgeSignature,
 
 // JwtEncode encodes JWT claims using the provided secret and algorithm
 func JwtEncode(claims map[string]interface{}, secret []byte, algorithm string) (string, error) {
-	token, err := jwt.Encode(claims, secret, algorithm)
-	if err != nil {
-		return "", err
+	method := jwt.GetSigningMethod(algorithm)
+	if method == nil {
+		return "", errors.New("Unsupported JWT algorithm")
 	}
-	return string(token), nil
+	return jwt.NewWithClaims(method, jwt.MapClaims(claims)).SignedString(secret)
 }
 
 // verifyJWT verifies a JSON Web Token (JWT) and returns decoded data if valid
 func (z *ZeroKnowledge) verifyJWT(tok []byte) (map[string]interface{}, error) {
-	if len(z.Secret) == 0 {
+	if z.Secret.Len() == 0 {
 		return nil, errors.New("JWT secret is empty")
 	}
-	return JwtDecode(tok, z.Secret, z.Issuer, z.Algorithm)
+	return JwtDecode(tok, z.Secret.Bytes(), z.Issuer, z.Algorithm)
 }
 
 // JwtDecode decodes a JWT using the provided secret, issuer, and algorithm
 func JwtDecode(tok []byte, secret []byte, issuer string, algorithm string) (map[string]interface{}, error) {
-	claims, err := jwt.Parse(string(tok), secret, issuer, algorithm)
+	token, err := jwt.Parse(string(tok), func(*jwt.Token) (interface{}, error) {
+		return secret, nil
+	}, jwt.WithValidMethods([]string{algorithm}))
 	if err != nil {
 		return nil, err
 	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !claims.VerifyIssuer(issuer, issuer != "") {
+		return nil, errors.New("Invalid JWT claims")
+	}
 	return claims, nil
 }
 
@@ -112,8 +133,12 @@ func (z *ZeroKnowledge) NewPoint(value interface{}) zkx_models.Point {
 		x, y := z.Curve.ScalarBaseMult(bigIntValue.Bytes())
 		return zkx_models.Point{X: x, Y: y}
 	case zkx_models.ZeroKnowledgeSignature:
-		// Assuming the value is already a point
-		return zkx_models.Point{X: value.X, Y: value.Y}
+		// The signature already holds a marshalled point
+		point, err := z.unmarshalPoint(v.Signature)
+		if err != nil {
+			return zkx_models.Point{}
+		}
+		return point
 	default:
 		// Handle other types if necessary
 		return zkx_models.Point{} // Return an empty point as default
@@ -121,41 +146,72 @@ func (z *ZeroKnowledge) NewPoint(value interface{}) zkx_models.Point {
 }
 
 // createSignature creates a signature object using the provided secret key
-func (z *ZeroKnowledge) CreateSignature(secret []byte) zkx_models.ZeroKnowledgeSignature {
-	signature := z.Hash(secret)
+func (z *ZeroKnowledge) CreateSignature(secret *zkx_types.SecretKey) zkx_models.ZeroKnowledgeSignature {
+	key := z.secretScalar(secret)
+	defer key.Destroy()
 	return zkx_models.ZeroKnowledgeSignature{
 		Params:    z.Params,
-		Signature: z.NewPoint(signature * z.Curve.Params().Gx),
+		Signature: z.marshalPoint(z.secretBaseMult(key)),
 	}
 }
 
 // createProof creates a proof object using the provided secret key and optional data
-func (z *ZeroKnowledge) CreateProof(secret []byte, data interface{}) zkx_models.ZeroKnowledgeProof {
-	key := z.Hash(secret)
-	r, _ := rand.Int(rand.Reader, big.NewInt(int64(z.Bits)))
-	Rx, Ry := z.Curve.ScalarBaseMult(r.Bytes())
-	R := zkx_models.Point{Rx, Ry}
-	c := z.Hash(data, R)
-	m := new(big.Int).Mod(new(big.Int).Sub(r, new(big.Int).Mul(c, key)), z.Curve.Params().N)
+func (z *ZeroKnowledge) CreateProof(secret *zkx_types.SecretKey, data interface{}) zkx_models.ZeroKnowledgeProof {
+	key := z.secretScalar(secret)
+	defer key.Destroy()
+	return z.CreateProofWithKey(key, data)
+}
+
+// CreateProofWithKey creates a proof object using an identity scalar instead of the raw secret
+func (z *ZeroKnowledge) CreateProofWithKey(key *zkx_types.Scalar, data interface{}) zkx_models.ZeroKnowledgeProof {
+	return z.createProof(z.basePoint(""), key, data)
+}
+
+// createProof creates a proof of knowledge of key for the public point key*base
+func (z *ZeroKnowledge) createProof(base zkx_models.Point, key *zkx_types.Scalar, data interface{}) zkx_models.ZeroKnowledgeProof {
+	r, _ := z.randomSecretScalar()
+	defer r.Destroy()
+	R := z.secretMult(base, r)
+	c := z.Hash(data, z.marshalPoint(R))
+
+	// m = r - c*key mod N, in constant time since both r and key are secret
+	m := new(zkx_types.Scalar).Sub(r, new(zkx_types.Scalar).Mul(z.toScalar(c), key))
 	return zkx_models.ZeroKnowledgeProof{
 		Params: z.Params,
 		C:      zkx_utils.IntToBytes(c),
-		M:      zkx_utils.IntToBytes(m),
+		M:      m.Bytes(),
 	}
 }
 
 // hash hashes the values provided modulo the curve order
 func (z *ZeroKnowledge) Hash(values ...interface{}) *big.Int {
+	hash := hashValues(values...)
+
+	// Convert the hash to a big.Int
+	hashInt := new(big.Int).SetBytes(hash[:])
+
+	// Reduce the hash modulo the curve order
+	return hashInt.Mod(hashInt, z.Curve.Params().N)
+}
+
+// hashValues concatenates the values and returns their SHA-256 digest
+func hashValues(values ...interface{}) [sha256.Size]byte {
 	// Concatenate all values into a single byte slice
 	var concatenated []byte
 	for _, value := range values {
 		switch v := value.(type) {
+		case nil:
+			continue
 		case int:
+			concatenated = append(concatenated, zkx_utils.IntToBytes(big.NewInt(int64(v)))...)
+		case *big.Int:
 			concatenated = append(concatenated, zkx_utils.IntToBytes(v)...)
 		case string:
 			concatenated = append(concatenated, []byte(v)...)
 		case []byte:
 			concatenated = append(concatenated, v...)
+		case *zkx_types.SecretKey:
+			concatenated = append(concatenated, v.Bytes()...)
 		default:
 			panic(errors.New("Unknown type"))
 		}
@@ -164,11 +220,20 @@ func (z *ZeroKnowledge) Hash(values ...interface{}) *big.Int {
 	// Calculate the hash of the concatenated byte slice
 	hash := sha256.Sum256(concatenated)
 
-	// Convert the hash to a big.Int
-	hashInt := new(big.Int).SetBytes(hash[:])
+	// Wipe the buffer, it may hold secret bytes
+	for i := range concatenated {
+		concatenated[i] = 0
+	}
+	return hash
+}
 
-	// Reduce the hash modulo the curve order
-	return hashInt.Mod(hashInt, z.Curve.Params().N)
+// hashable reports whether hashValues accepts the value, keep it in line with the cases above
+func hashable(value interface{}) bool {
+	switch value.(type) {
+	case nil, int, *big.Int, string, []byte, *zkx_types.SecretKey:
+		return true
+	}
+	return false
 }
 
 // _toPoint converts a value to a point on the elliptic curve
@@ -196,41 +261,83 @@ func (z *ZeroKnowledge) _toPoint(value interface{}) zkx_models.Point {
 
 }
 
-// Verify verifies a challenge against a signature and optional data
+// Verify verifies a challenge against a signature and optional data, context-bound proofs need VerifyContext
 func (z *ZeroKnowledge) Verify(challenge interface{}, signature zkx_models.ZeroKnowledgeSignature, data interface{}) bool {
 	// Convert the challenge to the appropriate type
-	var challengeData []byte
+	proof, data, ok := challengeProof(challenge, data)
+	if !ok {
+		return false
+	}
+
+	// A context-bound proof is only valid together with its audience and nonce, see VerifyContext
+	if proof.Context != nil {
+		return false
+	}
+	return z.verifyProof(proof, signature, data)
+}
+
+// verifyProof checks a proof over the data, including the context it is bound to
+func (z *ZeroKnowledge) verifyProof(proof zkx_models.ZeroKnowledgeProof, signature zkx_models.ZeroKnowledgeSignature, data interface{}) bool {
+	// A context-bound proof covers the context as well, so it cannot be stripped or altered
+	if proof.Context != nil {
+		data = contextTranscript(*proof.Context, data)
+	}
+
+	// Blind issuance keys answer any challenge, so they never stand for a login identity
+	if signature.Service == BlindIssuerService {
+		return false
+	}
+
+	// Hash-based identities carry a one-time signature instead of a Schnorr proof
+	if IsHashSignature(signature) {
+		return verifyHashProof(proof, signature, data)
+	}
+
+	// Decode the public point of the signer
+	publicPoint, err := z.unmarshalPoint(signature.Signature)
+	if err != nil {
+		return false
+	}
+
+	// Recompute the commitment R = m*B + c*Y, B is the generator unless the signature is a pseudonym
+	c := new(big.Int).SetBytes(proof.C)
+	m := new(big.Int).SetBytes(proof.M)
+	if m.Cmp(z.Curve.Params().N) >= 0 {
+		return false // m and m + N would be two proofs for one
+	}
+	R := z.commitment(z.basePoint(signature.Service), publicPoint, c, m)
+
+	// The proof holds when the challenge matches the hash of the data and the commitment
+	return c.Cmp(z.Hash(data, z.marshalPoint(R))) == 0
+}
+
+// challengeProof extracts the proof from a challenge and the data it was created over
+func challengeProof(challenge interface{}, data interface{}) (zkx_models.ZeroKnowledgeProof, interface{}, bool) {
 	switch c := challenge.(type) {
 	case zkx_models.ZeroKnowledgeData:
-		challengeData = []byte(c.Data)
+		if data == nil {
+			data = c.Data // The signed data travels with the proof
+		}
+		return c.Proof, data, true
 	case zkx_models.ZeroKnowledgeProof:
-		// Handle if necessary
+		return c, data, true
 	default:
-		// Handle other cases if needed
+		return zkx_models.ZeroKnowledgeProof{}, nil, false
 	}
-
-	// Compute the hash of the challenge data
-	hash := z.Hash(challengeData)
-
-	// Convert the signature to ecdsa.PublicKey
-	x, y := elliptic.Unmarshal(z.Curve.Params(), signature.Signature)
-	publicKey := ecdsa.PublicKey{Curve: z.Curve, X: x, Y: y}
-
-	// Verify the signature using the ecdsa.Verify function
-	return ecdsa.Verify(&publicKey, hash, signature.Signature)
 }
 
 // Sign creates a ZeroKnowledgeData object with a proof for the provided data
-func (z *ZeroKnowledge) Sign(secret []byte, data interface{}) *zkx_models.ZeroKnowledgeData {
-	proof := z.CreateProof(secret, data) // Create proof for the data
+func (z *ZeroKnowledge) Sign(secret *zkx_types.SecretKey, data interface{}) *zkx_models.ZeroKnowledgeData {
+	payload := fmt.Sprint(data)             // Render the data the way it is stored
+	proof := z.CreateProof(secret, payload) // Create proof for the data
 
 	return &zkx_models.ZeroKnowledgeData{
-		Data:  data,
+		Data:  payload,
 		Proof: proof,
 	}
 }
 
-// Token generates a random token of specified length in bytes
+// Token generates a random token of Bits bits, use a ChallengeService to issue login challenges
 func Token(z ZeroKnowledge) ([]byte, error) {
 	bytes := (z.Bits + 7) >> 3 // Calculate number of bytes based on bits
 	token := make([]byte, bytes)
@@ -243,13 +350,118 @@ func Token(z ZeroKnowledge) ([]byte, error) {
 
 // Login performs a login using the provided login data
 func (z *ZeroKnowledge) Login(loginData zkx_models.ZeroKnowledgeData) bool {
-	data, err := z.verifyJWT([]byte(loginData.Data))
-	if err != nil || data == nil {
+	signature, err := z.loginSignature(loginData)
+	if err != nil {
 		return false
 	}
+	return z.Verify(loginData, signature, nil)
+}
+
+// loginSignature verifies the JWT of the login data and returns the signature it was issued for
+func (z *ZeroKnowledge) loginSignature(loginData zkx_models.ZeroKnowledgeData) (zkx_models.ZeroKnowledgeSignature, error) {
+	data, err := z.verifyJWT([]byte(loginData.Data))
+	if err != nil {
+		return zkx_models.ZeroKnowledgeSignature{}, err
+	}
+	return jwtSignature(data)
+}
+
+// jwtSignature decodes the signature a verified JWT was issued for
+func jwtSignature(data map[string]interface{}) (zkx_models.ZeroKnowledgeSignature, error) {
 	signature := zkx_models.ZeroKnowledgeSignature{}
-	if err := json.Unmarshal([]byte(data["signature"].(string)), &signature); err != nil {
-		return false
+	signatureJSON, ok := data["signature"].(string)
+	if !ok {
+		return signature, errors.New("JWT carries no signature")
 	}
-	return z.Verify(loginData, signature, nil)
+	if err := json.Unmarshal([]byte(signatureJSON), &signature); err != nil {
+		return signature, err
+	}
+	return signature, nil
+}
+
+// marshalPoint encodes a point in the uncompressed form used by signatures
+func (z *ZeroKnowledge) marshalPoint(point zkx_models.Point) []byte {
+	return elliptic.Marshal(z.Curve, point.X, point.Y)
+}
+
+// unmarshalPoint decodes an uncompressed point and checks that it lies on the curve
+func (z *ZeroKnowledge) unmarshalPoint(data []byte) (zkx_models.Point, error) {
+	x, y := elliptic.Unmarshal(z.Curve, data)
+	if x == nil {
+		return zkx_models.Point{}, errors.New("Invalid curve point")
+	}
+	return zkx_models.Point{X: x, Y: y}, nil
+}
+
+// scalarMult multiplies a point by a public scalar
+func (z *ZeroKnowledge) scalarMult(point zkx_models.Point, k *big.Int) zkx_models.Point {
+	x, y := z.Curve.ScalarMult(point.X, point.Y, k.Bytes())
+	return zkx_models.Point{X: x, Y: y}
+}
+
+// commitment recomputes the prover commitment m*base + c*public of a Schnorr proof
+func (z *ZeroKnowledge) commitment(base zkx_models.Point, public zkx_models.Point, c *big.Int, m *big.Int) zkx_models.Point {
+	mb := z.scalarMult(base, m)
+	cy := z.scalarMult(public, c)
+	x, y := z.Curve.Add(mb.X, mb.Y, cy.X, cy.Y)
+	return zkx_models.Point{X: x, Y: y}
+}
+
+// scalarOrders caches the Montgomery constants of each curve order
+var scalarOrders sync.Map
+
+// scalarOrder returns the Montgomery constants of the curve order
+func (z *ZeroKnowledge) scalarOrder() *zkx_types.Modulus {
+	params := z.Curve.Params()
+	if order, ok := scalarOrders.Load(params.Name); ok {
+		return order.(*zkx_types.Modulus)
+	}
+	order, err := zkx_types.NewModulus(params.N)
+	if err != nil {
+		panic(err) // Every supported curve has an odd order of at most 256 bits
+	}
+	scalarOrders.Store(params.Name, order)
+	return order
+}
+
+// toScalar converts a public integer to a Scalar
+func (z *ZeroKnowledge) toScalar(value *big.Int) *zkx_types.Scalar {
+	scalar, _ := zkx_types.NewScalar(z.scalarOrder(), zkx_utils.IntToBytes(new(big.Int).Mod(value, z.Curve.Params().N)))
+	return scalar
+}
+
+// hashToScalar hashes the values like Hash, but reduces the digest in constant time
+func (z *ZeroKnowledge) hashToScalar(values ...interface{}) *zkx_types.Scalar {
+	hash := hashValues(values...)
+	scalar, _ := zkx_types.NewScalar(z.scalarOrder(), hash[:])
+	return scalar
+}
+
+// secretScalar derives the identity scalar of a secret, equal to Hash(secret)
+func (z *ZeroKnowledge) secretScalar(secret *zkx_types.SecretKey) *zkx_types.Scalar {
+	return z.hashToScalar(secret)
+}
+
+// randomSecretScalar draws a random non-zero Scalar for nonces and blinding factors
+func (z *ZeroKnowledge) randomSecretScalar() (*zkx_types.Scalar, error) {
+	return zkx_types.RandomScalar(z.scalarOrder(), rand.Reader)
+}
+
+// wipe overwrites a buffer that held secret bytes
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// secretBaseMult multiplies the generator by a secret Scalar, crypto/elliptic does this in constant time
+func (z *ZeroKnowledge) secretBaseMult(k *zkx_types.Scalar) zkx_models.Point {
+	x, y := z.Curve.ScalarBaseMult(k.Bytes())
+	return zkx_models.Point{X: x, Y: y}
+}
+
+// secretMult multiplies a point by a secret Scalar
+func (z *ZeroKnowledge) secretMult(point zkx_models.Point, k *zkx_types.Scalar) zkx_models.Point {
+	x, y := z.Curve.ScalarMult(point.X, point.Y, k.Bytes())
+	return zkx_models.Point{X: x, Y: y}
 }
@@ -0,0 +1,201 @@
+package core
+
+import (
+	"crypto/rand"                             // Import cryptographic random number generator
+	"crypto/sha256"                           // Import SHA-256 cryptographic hash function
+	"errors"                                  // Import package for error handling
+	"fmt"                                     // Import package for formatted I/O
+	"math/big"                                // Import package for big integer arithmetic
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+	zkx_utils "tmp/src/ZeroKnowledge/utils"   // Import Zero Knowledge utility functions
+)
+
+// The signer opens two sessions and completes only one of them, picked at random once the user
+// has committed to both challenges. This is the clause construction that defeats ROS attacks
+// against concurrent blind Schnorr sessions.
+//
+// The signer answers whatever challenge it is sent, so it signs with a key of its own: the blind
+// issuance key is derived from the secret apart from the login key, and its point is published with
+// the BlindIssuerService marker that Verify, Login and the registries refuse. A requester sending a
+// login transcript instead of a blinded challenge thus gets nothing that passes as the issuer. Blind
+// signatures also hash their own transcript, ending with a digest of the data rather than the
+// commitment, which keeps them apart from the data || R transcript of Verify.
+
+// BlindIssuerService marks the signature of a blind issuance key, it never verifies as a login identity
+const BlindIssuerService = "BlindSchnorr/Issuer"
+
+// CreateBlindIssuer creates the issuer signature users blind their data for, distinct from the login signature
+func (z *ZeroKnowledge) CreateBlindIssuer(secret *zkx_types.SecretKey) zkx_models.ZeroKnowledgeSignature {
+	key := z.blindKey(secret)
+	defer key.Destroy()
+	return zkx_models.ZeroKnowledgeSignature{
+		Params:    z.Params,
+		Signature: z.marshalPoint(z.secretBaseMult(key)),
+		Service:   BlindIssuerService,
+	}
+}
+
+// BlindCommit starts a blind signing run on the signer side
+func (z *ZeroKnowledge) BlindCommit() (*zkx_models.BlindSignerState, *zkx_models.BlindCommitment, error) {
+	k0, err := z.randomSecretScalar()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer k0.Destroy()
+	k1, err := z.randomSecretScalar()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer k1.Destroy()
+	state := &zkx_models.BlindSignerState{
+		K0: k0.Bytes(),
+		K1: k1.Bytes(),
+	}
+	commitment := &zkx_models.BlindCommitment{
+		R0: z.marshalPoint(z.secretBaseMult(k0)),
+		R1: z.marshalPoint(z.secretBaseMult(k1)),
+	}
+	return state, commitment, nil
+}
+
+// Blind hides the data from the signer and derives one blinded challenge per session
+func (z *ZeroKnowledge) Blind(issuer zkx_models.ZeroKnowledgeSignature, commitment zkx_models.BlindCommitment, data interface{}) (*zkx_models.BlindUserState, *zkx_models.BlindChallenge, error) {
+	public, err := z.blindIssuerPoint(issuer)
+	if err != nil {
+		return nil, nil, err
+	}
+	state := &zkx_models.BlindUserState{Data: fmt.Sprint(data)}
+	challenges := make([][]byte, 2)
+	for i, encoded := range [][]byte{commitment.R0, commitment.R1} {
+		R, err := z.unmarshalPoint(encoded)
+		if err != nil {
+			return nil, nil, err
+		}
+		alpha, err := z.randomSecretScalar()
+		if err != nil {
+			return nil, nil, err
+		}
+		beta, err := z.randomSecretScalar()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// R' = R + alpha*G - beta*Y is the commitment the verifier will recompute
+		ag := z.secretBaseMult(alpha)
+		by := z.secretMult(public, new(zkx_types.Scalar).Sub(z.toScalar(new(big.Int)), beta))
+		x, y := z.Curve.Add(R.X, R.Y, ag.X, ag.Y)
+		x, y = z.Curve.Add(x, y, by.X, by.Y)
+		c := z.blindChallenge(issuer, state.Data, zkx_models.Point{X: x, Y: y})
+
+		// The signer sees c + beta, which is uniformly distributed
+		blinded := new(zkx_types.Scalar).Add(z.toScalar(c), beta)
+		challenges[i] = blinded.Bytes()
+		state.Alphas = append(state.Alphas, alpha.Bytes())
+		state.Betas = append(state.Betas, beta.Bytes())
+		state.C = append(state.C, zkx_utils.IntToBytes(c))
+	}
+	return state, &zkx_models.BlindChallenge{C0: challenges[0], C1: challenges[1]}, nil
+}
+
+// BlindSign answers one randomly chosen session with the blind issuance key of the secret and burns the nonces of both
+func (z *ZeroKnowledge) BlindSign(secret *zkx_types.SecretKey, state *zkx_models.BlindSignerState, challenge zkx_models.BlindChallenge) (*zkx_models.BlindResponse, error) {
+	if len(state.K0) == 0 || len(state.K1) == 0 {
+		return nil, errors.New("Blind signing state was already used")
+	}
+	nonces := [][]byte{state.K0, state.K1}
+	challenges := [][]byte{challenge.C0, challenge.C1}
+	state.K0, state.K1 = nil, nil
+	defer wipe(nonces[0])
+	defer wipe(nonces[1])
+
+	bit, err := rand.Int(rand.Reader, big.NewInt(2))
+	if err != nil {
+		return nil, err
+	}
+	session := int(bit.Int64())
+
+	// m = k - c*x mod N with the blind issuance key, never the login key
+	k, err := zkx_types.NewScalar(z.scalarOrder(), nonces[session])
+	if err != nil {
+		return nil, err
+	}
+	defer k.Destroy()
+	key := z.blindKey(secret)
+	defer key.Destroy()
+	c := z.toScalar(new(big.Int).SetBytes(challenges[session]))
+	m := new(zkx_types.Scalar).Sub(k, new(zkx_types.Scalar).Mul(c, key))
+	return &zkx_models.BlindResponse{Session: session, M: m.Bytes()}, nil
+}
+
+// Unblind checks the signer response and turns it into a proof verifiable against the issuer signature
+func (z *ZeroKnowledge) Unblind(issuer zkx_models.ZeroKnowledgeSignature, state *zkx_models.BlindUserState, commitment zkx_models.BlindCommitment, challenge zkx_models.BlindChallenge, response zkx_models.BlindResponse) (*zkx_models.ZeroKnowledgeData, error) {
+	if response.Session != 0 && response.Session != 1 {
+		return nil, errors.New("Invalid blind session")
+	}
+	public, err := z.blindIssuerPoint(issuer)
+	if err != nil {
+		return nil, err
+	}
+	R, err := z.unmarshalPoint([][]byte{commitment.R0, commitment.R1}[response.Session])
+	if err != nil {
+		return nil, err
+	}
+
+	// The signer must have answered the challenge we sent for that session
+	c := new(big.Int).SetBytes([][]byte{challenge.C0, challenge.C1}[response.Session])
+	m := new(big.Int).SetBytes(response.M)
+	check := z.commitment(z.basePoint(""), public, c, m)
+	if check.X.Cmp(R.X) != 0 || check.Y.Cmp(R.Y) != 0 {
+		return nil, errors.New("Invalid blind signature response")
+	}
+
+	alpha, err := zkx_types.NewScalar(z.scalarOrder(), state.Alphas[response.Session])
+	if err != nil {
+		return nil, err
+	}
+	unblinded := new(zkx_types.Scalar).Add(z.toScalar(m), alpha)
+	return &zkx_models.ZeroKnowledgeData{
+		Data: state.Data,
+		Proof: zkx_models.ZeroKnowledgeProof{
+			Params: issuer.Params,
+			C:      state.C[response.Session],
+			M:      unblinded.Bytes(),
+		},
+	}, nil
+}
+
+// VerifyBlindSignature checks an unblinded signature against the issuer signature, Verify never accepts one
+func (z *ZeroKnowledge) VerifyBlindSignature(signature zkx_models.ZeroKnowledgeData, issuer zkx_models.ZeroKnowledgeSignature) bool {
+	proof := signature.Proof
+	if proof.Context != nil || proof.Hash != nil {
+		return false
+	}
+	public, err := z.blindIssuerPoint(issuer)
+	if err != nil {
+		return false
+	}
+	c := new(big.Int).SetBytes(proof.C)
+	m := new(big.Int).SetBytes(proof.M)
+	R := z.commitment(z.basePoint(""), public, c, m)
+	return c.Cmp(z.blindChallenge(issuer, signature.Data, R)) == 0
+}
+
+// blindChallenge hashes the blind signature transcript, distinct from the transcript of Verify
+func (z *ZeroKnowledge) blindChallenge(issuer zkx_models.ZeroKnowledgeSignature, data string, R zkx_models.Point) *big.Int {
+	digest := sha256.Sum256([]byte(data))
+	return z.Hash("BlindSchnorr", z.marshalPoint(R), issuer.Signature, digest[:])
+}
+
+// blindKey derives the blind issuance key of a secret, unrelated to its login key
+func (z *ZeroKnowledge) blindKey(secret *zkx_types.SecretKey) *zkx_types.Scalar {
+	return z.hashToScalar("BlindSchnorr", secret)
+}
+
+// blindIssuerPoint decodes the point of an issuer signature, which must be a blind issuance key
+func (z *ZeroKnowledge) blindIssuerPoint(issuer zkx_models.ZeroKnowledgeSignature) (zkx_models.Point, error) {
+	if issuer.Service != BlindIssuerService {
+		return zkx_models.Point{}, errors.New("Issuer signature is not a blind issuance key")
+	}
+	return z.unmarshalPoint(issuer.Signature)
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"testing"                                 // Import package for testing
+	"time"                                    // Import package for handling time
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+// blindRun runs one blind signing exchange between the issuer and a user
+func blindRun(t *testing.T, z *ZeroKnowledge, issuer zkx_models.ZeroKnowledgeSignature, data string) (*zkx_models.ZeroKnowledgeData, *zkx_models.BlindUserState, zkx_models.BlindCommitment, zkx_models.BlindChallenge, zkx_models.BlindResponse) {
+	t.Helper()
+	signerState, commitment, err := z.BlindCommit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	userState, challenge, err := z.Blind(issuer, *commitment, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := z.BlindSign(testSecret("issuer"), signerState, *challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := z.BlindSign(testSecret("issuer"), signerState, *challenge); err == nil {
+		t.Fatal("signer state was used twice")
+	}
+	signature, err := z.Unblind(issuer, userState, *commitment, *challenge, *response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signature, userState, *commitment, *challenge, *response
+}
+
+func TestBlindSignatureRoundTrip(t *testing.T) {
+	z := newTestZK(t)
+	issuer := z.CreateBlindIssuer(testSecret("issuer"))
+	signature, _, _, _, _ := blindRun(t, z, issuer, "coupon")
+	if !z.VerifyBlindSignature(*signature, issuer) {
+		t.Fatal("blind signature does not verify")
+	}
+	if z.VerifyBlindSignature(*signature, z.CreateBlindIssuer(testSecret("mallory"))) {
+		t.Fatal("blind signature verifies against another issuer")
+	}
+	signature.Data = "other coupon"
+	if z.VerifyBlindSignature(*signature, issuer) {
+		t.Fatal("blind signature verifies over other data")
+	}
+}
+
+func TestBlindSignatureRejectsTamperedResponse(t *testing.T) {
+	z := newTestZK(t)
+	issuer := z.CreateBlindIssuer(testSecret("issuer"))
+	_, userState, commitment, challenge, response := blindRun(t, z, issuer, "coupon")
+	response.M = append([]byte(nil), response.M...)
+	response.M[0] ^= 1
+	if _, err := z.Unblind(issuer, userState, commitment, challenge, response); err == nil {
+		t.Fatal("tampered response was unblinded")
+	}
+	response.Session = 2
+	if _, err := z.Unblind(issuer, userState, commitment, challenge, response); err == nil {
+		t.Fatal("response for an unknown session was unblinded")
+	}
+}
+
+// Regression: the issuer must not be usable as a signing oracle for proofs that Verify accepts
+func TestBlindSignatureIsDomainSeparated(t *testing.T) {
+	z := newTestZK(t)
+	issuer := z.CreateBlindIssuer(testSecret("issuer"))
+	login := z.CreateSignature(testSecret("issuer"))
+	signature, _, _, _, _ := blindRun(t, z, issuer, "login token")
+	for _, key := range []zkx_models.ZeroKnowledgeSignature{issuer, login} {
+		if z.Verify(*signature, key, nil) {
+			t.Fatal("blind signature verifies as a plain proof")
+		}
+	}
+	if z.Login(*signature) {
+		t.Fatal("blind signature logs in as the issuer")
+	}
+	plain := z.Sign(testSecret("issuer"), "login token")
+	if z.VerifyBlindSignature(*plain, issuer) {
+		t.Fatal("plain proof verifies as a blind signature")
+	}
+	if _, _, err := z.Blind(login, zkx_models.BlindCommitment{}, "data"); err == nil {
+		t.Fatal("blinded for a login signature")
+	}
+	if err := NewMemoryRegistry().Register("issuer", issuer); err == nil {
+		t.Fatal("registered a blind issuance key for login")
+	}
+}
+
+// Regression: a requester skipping Blind and sending the login transcript must not get a login proof
+func TestBlindSignRejectsLoginTranscript(t *testing.T) {
+	z := newTestZK(t)
+	issuer := z.CreateBlindIssuer(testSecret("issuer"))
+	login := z.CreateSignature(testSecret("issuer"))
+	signerState, commitment, err := z.BlindCommit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// c = Hash(data, R) for both sessions over a login token of the issuer, exactly what Login recomputes
+	data, err := z.GenerateJWT(login, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenges := make([][]byte, 2)
+	for i, R := range [][]byte{commitment.R0, commitment.R1} {
+		challenges[i] = z.Hash(data, R).Bytes()
+	}
+	response, err := z.BlindSign(testSecret("issuer"), signerState, zkx_models.BlindChallenge{C0: challenges[0], C1: challenges[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := zkx_models.ZeroKnowledgeData{
+		Data:  data,
+		Proof: zkx_models.ZeroKnowledgeProof{Params: z.Params, C: challenges[response.Session], M: response.M},
+	}
+	for _, key := range []zkx_models.ZeroKnowledgeSignature{login, issuer} {
+		if z.Verify(forged, key, nil) {
+			t.Fatal("raw login transcript answered by the blind signer verifies")
+		}
+	}
+	if z.VerifyBlindSignature(forged, issuer) {
+		t.Fatal("raw login transcript verifies as a blind signature")
+	}
+	if z.Login(forged) {
+		t.Fatal("blind signer response logs in as the issuer")
+	}
+}
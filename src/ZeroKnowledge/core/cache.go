@@ -0,0 +1,143 @@
+package core
+
+import (
+	"crypto/hmac"                             // Import package for keyed hashing
+	"crypto/sha256"                           // Import SHA-256 cryptographic hash function
+	"encoding/hex"                            // Import package for hexadecimal encoding
+	"encoding/json"                           // Import package for JSON encoding and decoding
+	"errors"                                  // Import package for error handling
+	"io/fs"                                   // Import package for file system errors
+	"os"                                      // Import package for file access
+	"path/filepath"                           // Import package for file paths
+	"sync"                                    // Import package for synchronization primitives
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+)
+
+// ProofCache remembers proofs that verified, so that repeated proofs are not verified again.
+// Only positive results are stored, a miss always falls back to a full verification.
+type ProofCache interface {
+	Contains(key []byte) bool
+	Add(key []byte) error
+}
+
+// MemoryProofCache keeps a bounded number of verified proofs in memory, evicting the oldest
+type MemoryProofCache struct {
+	mu       sync.Mutex          // Guards the fields below
+	capacity int                 // Maximum number of cached proofs
+	keys     map[string]struct{} // Cached proof digests
+	order    []string            // Cached proof digests, oldest first
+}
+
+// NewMemoryProofCache creates a cache holding up to capacity verified proofs
+func NewMemoryProofCache(capacity int) *MemoryProofCache {
+	return &MemoryProofCache{
+		capacity: capacity,
+		keys:     make(map[string]struct{}),
+	}
+}
+
+// Contains reports whether the proof digest is cached
+func (c *MemoryProofCache) Contains(key []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.keys[string(key)]
+	return ok
+}
+
+// Add caches a proof digest, evicting the oldest one when the cache is full
+func (c *MemoryProofCache) Add(key []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.keys[string(key)]; ok || c.capacity <= 0 {
+		return nil
+	}
+	if len(c.order) >= c.capacity {
+		delete(c.keys, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.keys[string(key)] = struct{}{}
+	c.order = append(c.order, string(key))
+	return nil
+}
+
+// FileProofCache keeps verified proofs on disk as empty files named after a keyed hash of their digest,
+// so that whoever can write to the directory still cannot plant an entry for a proof that never verified
+type FileProofCache struct {
+	Dir string               // Directory holding the cache entries
+	key *zkx_types.SecretKey // Key of the entry names
+}
+
+// NewFileProofCache creates a cache in the directory keyed with the secret, creating the directory if needed
+func NewFileProofCache(dir string, key *zkx_types.SecretKey) (*FileProofCache, error) {
+	if key.Len() == 0 {
+		return nil, errors.New("Proof cache needs a key")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileProofCache{Dir: dir, key: zkx_types.NewSecretKey(key.Bytes())}, nil
+}
+
+// Contains reports whether the proof digest is cached
+func (c *FileProofCache) Contains(key []byte) bool {
+	_, err := os.Stat(c.path(key))
+	return err == nil
+}
+
+// Add caches a proof digest
+func (c *FileProofCache) Add(key []byte) error {
+	file, err := os.OpenFile(c.path(key), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// path returns the file of a cache entry
+func (c *FileProofCache) path(key []byte) string {
+	mac := hmac.New(sha256.New, c.key.Bytes())
+	mac.Write(key)
+	return filepath.Join(c.Dir, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// VerifyCached works like Verify, but consults the cache first and caches proofs that verify
+func (z *ZeroKnowledge) VerifyCached(challenge interface{}, signature zkx_models.ZeroKnowledgeSignature, data interface{}, cache ProofCache) bool {
+	proof, proofData, ok := challengeProof(challenge, data)
+	if !ok || !hashable(proofData) {
+		return false
+	}
+	key := proofDigest(proof, signature, proofData)
+	if cache.Contains(key) {
+		return true
+	}
+	if !z.Verify(challenge, signature, data) {
+		return false
+	}
+	cache.Add(key) // A cache that cannot store only costs a later verification
+	return true
+}
+
+// proofDigest hashes a proof with everything its verification depends on
+func proofDigest(proof zkx_models.ZeroKnowledgeProof, signature zkx_models.ZeroKnowledgeSignature, data interface{}) []byte {
+	digest := hashValues(data) // The same bytes Verify hashes, so distinct data never share a key
+	fields := []interface{}{
+		"ProofCache",
+		signature.Params,
+		signature.Signature,
+		signature.Service,
+		canonicalInt(proof.C), // Verify reads integers, so padded encodings share a key
+		canonicalInt(proof.M),
+		proof.Context,
+		digest[:],
+	}
+	if proof.Hash != nil {
+		fields = append(fields, proof.Hash)
+	}
+	encoded, _ := json.Marshal(fields)
+	hash := sha256.Sum256(encoded)
+	return hash[:]
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"bytes"                                 // Import package for byte slice comparison
+	"encoding/hex"                          // Import package for hexadecimal encoding
+	"os"                                    // Import package for file access
+	"path/filepath"                         // Import package for file paths
+	"testing"                               // Import package for testing
+	zkx_types "tmp/src/ZeroKnowledge/types" // Import Zero Knowledge types
+)
+
+func TestVerifyCached(t *testing.T) {
+	z := newTestZK(t)
+	secret := testSecret("alice")
+	signature := z.CreateSignature(secret)
+	cache, err := NewFileProofCache(t.TempDir(), testSecret("cache"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := z.Sign(secret, "login")
+	for i := 0; i < 2; i++ {
+		if !z.VerifyCached(*signed, signature, nil, cache) {
+			t.Fatalf("valid proof rejected on verification %d", i)
+		}
+	}
+	if z.VerifyCached(*signed, z.CreateSignature(testSecret("mallory")), nil, cache) {
+		t.Fatal("cached proof verifies against another signature")
+	}
+	if z.VerifyCached(*signed, signature, "other", cache) {
+		t.Fatal("cached proof verifies over other data")
+	}
+	forged := *signed
+	forged.Proof.M = append([]byte(nil), signed.Proof.M...)
+	forged.Proof.M[0] ^= 1
+	if z.VerifyCached(forged, signature, nil, NewMemoryProofCache(4)) {
+		t.Fatal("tampered proof verifies")
+	}
+}
+
+// Regression: padded encodings of the same proof must share one cache key
+func TestProofDigestIgnoresPadding(t *testing.T) {
+	z := newTestZK(t)
+	secret := testSecret("alice")
+	signature := z.CreateSignature(secret)
+	signed := z.Sign(secret, "login")
+	padded := signed.Proof
+	padded.C = append([]byte{0}, signed.Proof.C...)
+	padded.M = append([]byte{0, 0}, signed.Proof.M...)
+	if !bytes.Equal(proofDigest(signed.Proof, signature, signed.Data), proofDigest(padded, signature, signed.Data)) {
+		t.Fatal("padding changes the cache key")
+	}
+}
+
+// Regression: an entry planted under the digest of a forged proof must not make it verify
+func TestFileProofCacheIsKeyed(t *testing.T) {
+	z := newTestZK(t)
+	signature := z.CreateSignature(testSecret("alice"))
+	dir := t.TempDir()
+	if _, err := NewFileProofCache(dir, zkx_types.NewSecretKey(nil)); err == nil {
+		t.Fatal("created a cache without a key")
+	}
+	cache, err := NewFileProofCache(dir, testSecret("cache"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := *z.Sign(testSecret("mallory"), "login")
+	planted := filepath.Join(dir, hex.EncodeToString(proofDigest(forged.Proof, signature, forged.Data)))
+	if err := os.WriteFile(planted, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if z.VerifyCached(forged, signature, nil, cache) {
+		t.Fatal("planted entry makes a forged proof verify")
+	}
+
+	signed := z.Sign(testSecret("alice"), "login")
+	if !z.VerifyCached(*signed, signature, nil, cache) {
+		t.Fatal("valid proof rejected")
+	}
+	other, err := NewFileProofCache(dir, testSecret("other cache"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Contains(proofDigest(signed.Proof, signature, signed.Data)) {
+		t.Fatal("entry is visible under another key")
+	}
+}
+
+// Regression: data Verify cannot hash is refused instead of panicking
+func TestVerifyCachedRejectsUnsupportedData(t *testing.T) {
+	z := newTestZK(t)
+	signature := z.CreateSignature(testSecret("alice"))
+	signed := z.Sign(testSecret("alice"), "login")
+	if z.VerifyCached(signed.Proof, signature, 1.5, NewMemoryProofCache(4)) {
+		t.Fatal("proof verifies over unsupported data")
+	}
+}
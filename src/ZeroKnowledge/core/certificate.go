@@ -0,0 +1,113 @@
+package core
+
+import (
+	"encoding/json"                           // Import package for JSON encoding and decoding
+	"errors"                                  // Import package for error handling
+	"time"                                    // Import package for handling time
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_utils "tmp/src/ZeroKnowledge/utils"   // Import Zero Knowledge utility functions
+)
+
+// A certificate chain starts at the certificate of the user and every certificate is signed by the
+// subject of the next one, the last by a root the relying service pins. Verification needs nothing but
+// the chain, the pinned roots and the clock, so relying services never query the registry.
+
+// MaxChainLength is the number of certificates a chain may hold, including the one of the user
+const MaxChainLength = 8
+
+// certificateSerialSize is the number of random bytes in a serial number
+const certificateSerialSize = 16
+
+// IssueCertificate signs the template with the key of an authority, curve-based or hash-based.
+// The serial number, issuer and proof are filled in, the validity period is rounded to seconds.
+func IssueCertificate(issuer LoginSigner, template zkx_models.Certificate) (*zkx_models.Certificate, error) {
+	if template.Subject == "" || len(template.Signature.Signature) == 0 {
+		return nil, errors.New("Certificate needs a subject and a signature")
+	}
+	if !template.NotAfter.After(template.NotBefore) {
+		return nil, errors.New("Certificate validity period is empty")
+	}
+	certificate := template
+	certificate.Serial = zkx_utils.GenerateSalt(certificateSerialSize)
+	certificate.Issuer = issuer.Signature()
+	certificate.NotBefore = template.NotBefore.UTC().Truncate(time.Second)
+	certificate.NotAfter = template.NotAfter.UTC().Truncate(time.Second)
+	signed, err := issuer.Sign(certificateContent(certificate))
+	if err != nil {
+		return nil, err
+	}
+	certificate.Proof = signed.Proof
+	return &certificate, nil
+}
+
+// VerifyCertificate checks the proof of the issuer and the validity period of a single certificate
+func (z *ZeroKnowledge) VerifyCertificate(certificate zkx_models.Certificate, at time.Time) error {
+	if !z.Verify(certificate.Proof, certificate.Issuer, certificateContent(certificate)) {
+		return zkx_errors.ErrCertificateProof
+	}
+	if at.Before(certificate.NotBefore) || at.After(certificate.NotAfter) {
+		return zkx_errors.ErrCertificateTime
+	}
+	return nil
+}
+
+// VerifyChain checks a chain from the certificate of the user up to one of the pinned roots
+func (z *ZeroKnowledge) VerifyChain(chain []zkx_models.Certificate, roots []zkx_models.ZeroKnowledgeSignature, at time.Time) error {
+	if len(chain) == 0 || len(chain) > MaxChainLength {
+		return zkx_errors.ErrCertificateChain
+	}
+	for i, certificate := range chain {
+		if err := z.VerifyCertificate(certificate, at); err != nil {
+			return err
+		}
+		if i == len(chain)-1 {
+			break
+		}
+
+		// The next certificate must belong to the issuer and allow it to issue certificates
+		next := chain[i+1]
+		if !next.Authority || !sameSignature(certificate.Issuer, next.Signature) {
+			return zkx_errors.ErrCertificateChain
+		}
+	}
+	last := chain[len(chain)-1]
+	for _, root := range roots {
+		if sameSignature(last.Issuer, root) {
+			return nil
+		}
+	}
+	return zkx_errors.ErrUntrustedRoot
+}
+
+// VerifyCertifiedLogin checks the chain and the login proof over the challenge the relying service issued,
+// and returns the certificate of the user
+func (z *ZeroKnowledge) VerifyCertifiedLogin(login zkx_models.CertifiedLogin, roots []zkx_models.ZeroKnowledgeSignature, challenge string, at time.Time) (*zkx_models.Certificate, error) {
+	if err := z.VerifyChain(login.Chain, roots, at); err != nil {
+		return nil, err
+	}
+	if login.Login.Data != challenge {
+		return nil, zkx_errors.ErrUnknownChallenge
+	}
+	certificate := login.Chain[0]
+	if !z.Verify(login.Login, certificate.Signature, nil) {
+		return nil, zkx_errors.ErrInvalidProof
+	}
+	return &certificate, nil
+}
+
+// certificateContent encodes everything the issuer signs, times as Unix seconds so that they survive JSON
+func certificateContent(certificate zkx_models.Certificate) string {
+	content, _ := json.Marshal([]interface{}{
+		"Certificate",
+		certificate.Serial,
+		certificate.Subject,
+		certificate.Signature,
+		certificate.Issuer,
+		certificate.NotBefore.Unix(),
+		certificate.NotAfter.Unix(),
+		certificate.Attributes,
+		certificate.Authority,
+	})
+	return string(content)
+}
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"encoding/json"                           // Import package for JSON encoding and decoding
+	"errors"                                  // Import package for error handling
+	"testing"                                 // Import package for testing
+	"time"                                    // Import package for handling time
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+// testCertificate issues a certificate for the named subject, valid for an hour around now
+func testCertificate(t *testing.T, z *ZeroKnowledge, issuer LoginSigner, subject string, authority bool) zkx_models.Certificate {
+	t.Helper()
+	now := time.Now()
+	certificate, err := IssueCertificate(issuer, zkx_models.Certificate{
+		Subject:    subject,
+		Signature:  z.CreateSignature(testSecret(subject)),
+		NotBefore:  now.Add(-time.Hour),
+		NotAfter:   now.Add(time.Hour),
+		Attributes: map[string]string{"role": "member"},
+		Authority:  authority,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *certificate
+}
+
+// testChain issues a chain from the root through an intermediate authority to alice
+func testChain(t *testing.T, z *ZeroKnowledge, root LoginSigner) []zkx_models.Certificate {
+	t.Helper()
+	intermediate := testCertificate(t, z, root, "intermediate", true)
+	user := testCertificate(t, z, NewCurveSigner(z, testSecret("intermediate")), "alice", false)
+	return []zkx_models.Certificate{user, intermediate}
+}
+
+func TestCertificateChainRoundTrip(t *testing.T) {
+	z := newTestZK(t)
+	root := NewCurveSigner(z, testSecret("root"))
+	chain := testChain(t, z, root)
+	login := zkx_models.CertifiedLogin{Chain: chain, Login: *z.Sign(testSecret("alice"), "challenge")}
+
+	// The chain and login travel as JSON
+	data, err := json.Marshal(login)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := zkx_models.CertifiedLogin{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := z.VerifyCertifiedLogin(decoded, []zkx_models.ZeroKnowledgeSignature{root.Signature()}, "challenge", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if certificate.Subject != "alice" || certificate.Attributes["role"] != "member" {
+		t.Fatalf("login certified %q", certificate.Subject)
+	}
+	if _, err := z.VerifyCertifiedLogin(decoded, []zkx_models.ZeroKnowledgeSignature{root.Signature()}, "other", time.Now()); !errors.Is(err, zkx_errors.ErrUnknownChallenge) {
+		t.Fatalf("login over another challenge gave %v", err)
+	}
+	decoded.Login = *z.Sign(testSecret("mallory"), "challenge")
+	if _, err := z.VerifyCertifiedLogin(decoded, []zkx_models.ZeroKnowledgeSignature{root.Signature()}, "challenge", time.Now()); !errors.Is(err, zkx_errors.ErrInvalidProof) {
+		t.Fatalf("login of another user gave %v", err)
+	}
+}
+
+func TestCertificateHashBasedRoot(t *testing.T) {
+	z := newTestZK(t)
+	key, err := z.GenerateHashKey("XMSS-SHA2_10_256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := NewHashSigner(key, NewMemoryHashKeyState())
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := testChain(t, z, root)
+	if err := z.VerifyChain(chain, []zkx_models.ZeroKnowledgeSignature{root.Signature()}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCertificateChainRejectsTampering(t *testing.T) {
+	z := newTestZK(t)
+	root := NewCurveSigner(z, testSecret("root"))
+	roots := []zkx_models.ZeroKnowledgeSignature{root.Signature()}
+	chain := testChain(t, z, root)
+
+	forged := append([]zkx_models.Certificate(nil), chain...)
+	forged[0].Attributes = map[string]string{"role": "admin"}
+	if err := z.VerifyChain(forged, roots, time.Now()); !errors.Is(err, zkx_errors.ErrCertificateProof) {
+		t.Fatalf("changed attribute gave %v", err)
+	}
+	forged = append([]zkx_models.Certificate(nil), chain...)
+	forged[0].NotAfter = forged[0].NotAfter.Add(time.Hour)
+	if err := z.VerifyChain(forged, roots, time.Now()); !errors.Is(err, zkx_errors.ErrCertificateProof) {
+		t.Fatalf("extended validity gave %v", err)
+	}
+	if err := z.VerifyChain(chain, roots, time.Now().Add(2*time.Hour)); !errors.Is(err, zkx_errors.ErrCertificateTime) {
+		t.Fatalf("expired chain gave %v", err)
+	}
+	if err := z.VerifyChain(chain[:1], roots, time.Now()); !errors.Is(err, zkx_errors.ErrUntrustedRoot) {
+		t.Fatalf("chain without its intermediate gave %v", err)
+	}
+	if err := z.VerifyChain(chain, []zkx_models.ZeroKnowledgeSignature{z.CreateSignature(testSecret("other root"))}, time.Now()); !errors.Is(err, zkx_errors.ErrUntrustedRoot) {
+		t.Fatalf("chain to another root gave %v", err)
+	}
+	if err := z.VerifyChain(nil, roots, time.Now()); !errors.Is(err, zkx_errors.ErrCertificateChain) {
+		t.Fatalf("empty chain gave %v", err)
+	}
+
+	// A certificate that is not an authority cannot issue certificates
+	leaf := testCertificate(t, z, root, "intermediate", false)
+	user := testCertificate(t, z, NewCurveSigner(z, testSecret("intermediate")), "alice", false)
+	if err := z.VerifyChain([]zkx_models.Certificate{user, leaf}, roots, time.Now()); !errors.Is(err, zkx_errors.ErrCertificateChain) {
+		t.Fatalf("chain through a non-authority gave %v", err)
+	}
+
+	// Chains longer than MaxChainLength are refused even if every link holds
+	long := []zkx_models.Certificate{}
+	issuer := LoginSigner(root)
+	names := []string{}
+	for i := 0; i < MaxChainLength; i++ {
+		names = append(names, "authority "+string(rune('a'+i)))
+	}
+	for _, name := range names {
+		long = append([]zkx_models.Certificate{testCertificate(t, z, issuer, name, true)}, long...)
+		issuer = NewCurveSigner(z, testSecret(name))
+	}
+	long = append([]zkx_models.Certificate{testCertificate(t, z, issuer, "alice", false)}, long...)
+	if err := z.VerifyChain(long[:MaxChainLength], nil, time.Now()); !errors.Is(err, zkx_errors.ErrUntrustedRoot) {
+		t.Fatalf("chain of the maximum length gave %v", err)
+	}
+	if err := z.VerifyChain(long, roots, time.Now()); !errors.Is(err, zkx_errors.ErrCertificateChain) {
+		t.Fatalf("chain above the maximum length gave %v", err)
+	}
+}
+
+func TestIssueCertificateRejectsBadTemplates(t *testing.T) {
+	z := newTestZK(t)
+	root := NewCurveSigner(z, testSecret("root"))
+	now := time.Now()
+	if _, err := IssueCertificate(root, zkx_models.Certificate{Signature: z.CreateSignature(testSecret("alice")), NotBefore: now, NotAfter: now.Add(time.Hour)}); err == nil {
+		t.Fatal("issued a certificate without a subject")
+	}
+	if _, err := IssueCertificate(root, zkx_models.Certificate{Subject: "alice", Signature: z.CreateSignature(testSecret("alice")), NotBefore: now, NotAfter: now}); err == nil {
+		t.Fatal("issued a certificate with an empty validity period")
+	}
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"crypto/subtle"                           // Import package for constant-time comparison
+	"encoding/base64"                         // Import package for base64 decoding
+	"sync"                                    // Import package for synchronization primitives
+	"time"                                    // Import package for handling time
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+// ChallengeStore holds the challenges that were issued and not used yet
+type ChallengeStore interface {
+	Put(challenge zkx_models.Challenge) error
+	Take(user string, session string, value []byte) (zkx_models.Challenge, bool, error)
+}
+
+// MemoryChallengeStore keeps one pending challenge per user and session in memory
+type MemoryChallengeStore struct {
+	mu         sync.Mutex                      // Guards the map below
+	challenges map[string]zkx_models.Challenge // Pending challenges by user and session
+}
+
+// NewMemoryChallengeStore creates a new, empty MemoryChallengeStore
+func NewMemoryChallengeStore() *MemoryChallengeStore {
+	return &MemoryChallengeStore{challenges: make(map[string]zkx_models.Challenge)}
+}
+
+// Put stores a challenge, replacing the pending one of the same user and session
+func (s *MemoryChallengeStore) Put(challenge zkx_models.Challenge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for key, pending := range s.challenges {
+		if now.After(pending.Expires) {
+			delete(s.challenges, key)
+		}
+	}
+	s.challenges[challengeKey(challenge.User, challenge.Session)] = challenge
+	return nil
+}
+
+// Take removes and returns the pending challenge of the user and session if it has the value, a pending
+// challenge with another value stays, so that an old answer cannot burn the one just issued
+func (s *MemoryChallengeStore) Take(user string, session string, value []byte) (zkx_models.Challenge, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := challengeKey(user, session)
+	challenge, ok := s.challenges[key]
+	if !ok || subtle.ConstantTimeCompare(challenge.Value, value) != 1 {
+		return zkx_models.Challenge{}, false, nil
+	}
+	delete(s.challenges, key)
+	return challenge, true, nil
+}
+
+// ChallengeService issues unique, expiring, single-use login challenges
+type ChallengeService struct {
+	ZK       *ZeroKnowledge // Instance the challenges are sized and verified with
+	Store    ChallengeStore // Storage of pending challenges
+	Lifetime time.Duration  // How long a challenge can be answered
+}
+
+// NewChallengeService creates a service issuing challenges into the store
+func NewChallengeService(z *ZeroKnowledge, store ChallengeStore, lifetime time.Duration) *ChallengeService {
+	return &ChallengeService{ZK: z, Store: store, Lifetime: lifetime}
+}
+
+// Issue creates a challenge for the session of the user, a newer challenge replaces an older one
+func (s *ChallengeService) Issue(user string, session string) (*zkx_models.Challenge, error) {
+	value, err := Token(*s.ZK)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	challenge := zkx_models.Challenge{
+		User:    user,
+		Session: session,
+		Value:   value,
+		Issued:  now,
+		Expires: now.Add(s.Lifetime),
+	}
+	if err := s.Store.Put(challenge); err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// Verify checks a proof over the challenge of the session and consumes the challenge
+func (s *ChallengeService) Verify(user string, session string, loginData zkx_models.ZeroKnowledgeData, signature zkx_models.ZeroKnowledgeSignature) error {
+	value, err := base64.StdEncoding.DecodeString(loginData.Data)
+	if err != nil || !s.ZK.Verify(loginData, signature, nil) {
+		return zkx_errors.ErrInvalidProof
+	}
+
+	// A valid proof over the pending challenge takes it, so it can never be answered twice
+	challenge, ok, err := s.Store.Take(user, session, value)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return zkx_errors.ErrUnknownChallenge
+	}
+	if time.Now().After(challenge.Expires) {
+		return zkx_errors.ErrChallengeExpired
+	}
+	return nil
+}
+
+// challengeKey identifies the pending challenge of a user and session
+func challengeKey(user string, session string) string {
+	return user + "\x00" + session
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"encoding/base64"                         // Import package for base64 encoding
+	"errors"                                  // Import package for error handling
+	"testing"                                 // Import package for testing
+	"time"                                    // Import package for handling time
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+// answer signs the value of a challenge as alice
+func answer(z *ZeroKnowledge, challenge *zkx_models.Challenge) zkx_models.ZeroKnowledgeData {
+	return *z.Sign(testSecret("alice"), base64.StdEncoding.EncodeToString(challenge.Value))
+}
+
+func TestChallengeRoundTrip(t *testing.T) {
+	z := newTestZK(t)
+	signature := z.CreateSignature(testSecret("alice"))
+	service := NewChallengeService(z, NewMemoryChallengeStore(), time.Minute)
+	challenge, err := service.Issue("alice", "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	login := answer(z, challenge)
+	if err := service.Verify("alice", "other session", login, signature); !errors.Is(err, zkx_errors.ErrUnknownChallenge) {
+		t.Fatalf("answer in another session: %v", err)
+	}
+	if err := service.Verify("alice", "session", login, z.CreateSignature(testSecret("mallory"))); !errors.Is(err, zkx_errors.ErrInvalidProof) {
+		t.Fatalf("answer of another identity: %v", err)
+	}
+	if err := service.Verify("alice", "session", login, signature); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Verify("alice", "session", login, signature); !errors.Is(err, zkx_errors.ErrUnknownChallenge) {
+		t.Fatalf("replayed answer: %v", err)
+	}
+}
+
+func TestChallengeExpires(t *testing.T) {
+	z := newTestZK(t)
+	service := NewChallengeService(z, NewMemoryChallengeStore(), -time.Second)
+	challenge, err := service.Issue("alice", "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Verify("alice", "session", answer(z, challenge), z.CreateSignature(testSecret("alice"))); !errors.Is(err, zkx_errors.ErrChallengeExpired) {
+		t.Fatalf("late answer: %v", err)
+	}
+}
+
+// Regression: replaying an old answer must not burn the challenge issued after it
+func TestChallengeReplayKeepsPending(t *testing.T) {
+	z := newTestZK(t)
+	signature := z.CreateSignature(testSecret("alice"))
+	service := NewChallengeService(z, NewMemoryChallengeStore(), time.Minute)
+	old, err := service.Issue("alice", "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed := answer(z, old)
+	if err := service.Verify("alice", "session", replayed, signature); err != nil {
+		t.Fatal(err)
+	}
+
+	fresh, err := service.Issue("alice", "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Verify("alice", "session", replayed, signature); !errors.Is(err, zkx_errors.ErrUnknownChallenge) {
+		t.Fatalf("replayed answer: %v", err)
+	}
+	if err := service.Verify("alice", "session", answer(z, fresh), signature); err != nil {
+		t.Fatalf("fresh challenge was burnt by the replay: %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package core
+
+import (
+	"crypto/hmac"                             // Import package for constant-time comparison
+	"encoding/json"                           // Import package for JSON encoding and decoding
+	"fmt"                                     // Import package for formatted I/O
+	"sync"                                    // Import package for synchronization primitives
+	"time"                                    // Import package for handling time
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+	zkx_utils "tmp/src/ZeroKnowledge/utils"   // Import Zero Knowledge utility functions
+)
+
+// ContextLeeway is the clock skew tolerated between prover and verifier
+const ContextLeeway = 30 * time.Second
+
+// contextNonceSize is the number of random bytes in a nonce issued by a NonceStore
+const contextNonceSize = 16
+
+// NonceStore issues the nonces of a verifier and remembers which of them were already used
+type NonceStore interface {
+	Issue(expires time.Time) ([]byte, error)
+	Use(nonce []byte, now time.Time) error
+}
+
+// MemoryNonceStore keeps issued and used nonces in memory until they expire
+type MemoryNonceStore struct {
+	mu     sync.Mutex           // Guards the maps below
+	issued map[string]time.Time // Expiry of every nonce not used yet
+	used   map[string]time.Time // Expiry of every nonce already used
+}
+
+// NewMemoryNonceStore creates a new, empty MemoryNonceStore
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{
+		issued: make(map[string]time.Time),
+		used:   make(map[string]time.Time),
+	}
+}
+
+// Issue creates a fresh random nonce that can be used once before it expires
+func (s *MemoryNonceStore) Issue(expires time.Time) ([]byte, error) {
+	nonce := zkx_utils.GenerateSalt(contextNonceSize)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(time.Now())
+	s.issued[string(nonce)] = expires
+	return nonce, nil
+}
+
+// Use consumes a nonce, it fails for nonces that were never issued, have expired or were used before
+func (s *MemoryNonceStore) Use(nonce []byte, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(now)
+	key := string(nonce)
+	if _, ok := s.used[key]; ok {
+		return zkx_errors.ErrNonceReused
+	}
+	expires, ok := s.issued[key]
+	if !ok {
+		return zkx_errors.ErrUnknownNonce
+	}
+	delete(s.issued, key)
+	s.used[key] = expires
+	return nil
+}
+
+// prune forgets the nonces that expired before now, callers must hold the lock
+func (s *MemoryNonceStore) prune(now time.Time) {
+	for key, expires := range s.issued {
+		if now.After(expires) {
+			delete(s.issued, key)
+		}
+	}
+	for key, expires := range s.used {
+		if now.After(expires) {
+			delete(s.used, key)
+		}
+	}
+}
+
+// NewProofContext creates a context for the audience and nonce that is valid for the given lifetime. The binding
+// is the fingerprint of the session the proof is sent over, such as HMACClient.Fingerprint, or nil for none.
+func NewProofContext(audience string, nonce []byte, binding []byte, lifetime time.Duration) zkx_models.ProofContext {
+	now := time.Now().UTC()
+	return zkx_models.ProofContext{
+		Audience: audience,
+		Nonce:    nonce,
+		Binding:  binding,
+		IssuedAt: now,
+		Expires:  now.Add(lifetime),
+	}
+}
+
+// SignWithContext works like Sign, but binds the proof to the context as well as to the data
+func (z *ZeroKnowledge) SignWithContext(secret *zkx_types.SecretKey, data interface{}, context zkx_models.ProofContext) *zkx_models.ZeroKnowledgeData {
+	payload := fmt.Sprint(data)
+	proof := z.CreateProof(secret, contextTranscript(context, payload))
+	proof.Context = &context
+	return &zkx_models.ZeroKnowledgeData{
+		Data:  payload,
+		Proof: proof,
+	}
+}
+
+// VerifyContext checks a context-bound proof for the audience and the session fingerprint the verifier holds,
+// nil when the proof is not sent over a bound session, and consumes its nonce. A proof committing to another
+// session is refused, so that a login cannot be spliced onto another channel.
+func (z *ZeroKnowledge) VerifyContext(signed zkx_models.ZeroKnowledgeData, signature zkx_models.ZeroKnowledgeSignature, audience string, binding []byte, nonces NonceStore) error {
+	context := signed.Proof.Context
+	if context == nil {
+		return zkx_errors.ErrMissingContext
+	}
+	if context.Audience != audience {
+		return zkx_errors.ErrWrongAudience
+	}
+	if !hmac.Equal(context.Binding, binding) {
+		return zkx_errors.ErrChannelBinding
+	}
+	now := time.Now()
+	if now.Add(ContextLeeway).Before(context.IssuedAt) {
+		return zkx_errors.ErrNotYetValid
+	}
+	if now.Add(-ContextLeeway).After(context.Expires) {
+		return zkx_errors.ErrProofExpired
+	}
+	if !z.verifyProof(signed.Proof, signature, signed.Data) {
+		return zkx_errors.ErrInvalidProof
+	}
+
+	// Only a valid proof may burn the nonce, otherwise anyone could exhaust nonces of others
+	return nonces.Use(context.Nonce, now)
+}
+
+// LoginWithContext performs a login like Login and additionally enforces the context of the proof
+func (z *ZeroKnowledge) LoginWithContext(loginData zkx_models.ZeroKnowledgeData, audience string, binding []byte, nonces NonceStore) error {
+	signature, err := z.loginSignature(loginData)
+	if err != nil {
+		return err
+	}
+	return z.VerifyContext(loginData, signature, audience, binding, nonces)
+}
+
+// contextTranscript encodes the context together with the data a context-bound proof is created over
+func contextTranscript(context zkx_models.ProofContext, data interface{}) string {
+	transcript, _ := json.Marshal([]interface{}{
+		"ProofContext",
+		context.Audience,
+		context.Nonce,
+		context.IssuedAt.Unix(),
+		context.Expires.Unix(),
+		context.Binding,
+		fmt.Sprint(data),
+	})
+	return string(transcript)
+}
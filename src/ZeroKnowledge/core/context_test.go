@@ -0,0 +1,126 @@
+package core
+
+import (
+	"errors"                                  // Import package for error handling
+	"testing"                                 // Import package for testing
+	"time"                                    // Import package for handling time
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+// contextLogin signs a login token of alice bound to the audience and a fresh nonce of the store
+func contextLogin(t *testing.T, z *ZeroKnowledge, nonces NonceStore, audience string) (*zkx_models.ZeroKnowledgeData, zkx_models.ZeroKnowledgeSignature) {
+	t.Helper()
+	signature := z.CreateSignature(testSecret("alice"))
+	token, err := z.GenerateJWT(signature, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce, err := nonces.Issue(time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return z.SignWithContext(testSecret("alice"), token, NewProofContext(audience, nonce, nil, time.Minute)), signature
+}
+
+func TestContextLogin(t *testing.T) {
+	z := newTestZK(t)
+	nonces := NewMemoryNonceStore()
+	login, signature := contextLogin(t, z, nonces, "service")
+	if err := z.VerifyContext(*login, z.CreateSignature(testSecret("mallory")), "service", nil, nonces); !errors.Is(err, zkx_errors.ErrInvalidProof) {
+		t.Fatalf("proof of another identity: %v", err)
+	}
+	if err := z.LoginWithContext(*login, "service", nil, nonces); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.VerifyContext(*login, signature, "service", nil, nonces); !errors.Is(err, zkx_errors.ErrNonceReused) {
+		t.Fatalf("replayed proof: %v", err)
+	}
+}
+
+func TestContextRejections(t *testing.T) {
+	z := newTestZK(t)
+	nonces := NewMemoryNonceStore()
+	login, signature := contextLogin(t, z, nonces, "service")
+	if err := z.VerifyContext(*login, signature, "other service", nil, nonces); !errors.Is(err, zkx_errors.ErrWrongAudience) {
+		t.Fatalf("proof for another audience: %v", err)
+	}
+	if err := z.VerifyContext(*login, signature, "service", nil, NewMemoryNonceStore()); !errors.Is(err, zkx_errors.ErrUnknownNonce) {
+		t.Fatalf("nonce of another verifier: %v", err)
+	}
+
+	// Altering the context breaks the proof, which covers it
+	altered := *login
+	context := *login.Proof.Context
+	context.Expires = context.Expires.Add(time.Hour)
+	altered.Proof.Context = &context
+	if err := z.VerifyContext(altered, signature, "service", nil, nonces); !errors.Is(err, zkx_errors.ErrInvalidProof) {
+		t.Fatalf("altered context: %v", err)
+	}
+	context.Expires = time.Now().Add(-time.Hour)
+	if err := z.VerifyContext(altered, signature, "service", nil, nonces); !errors.Is(err, zkx_errors.ErrProofExpired) {
+		t.Fatalf("expired context: %v", err)
+	}
+
+	plain := z.Sign(testSecret("alice"), login.Data)
+	if err := z.VerifyContext(*plain, signature, "service", nil, nonces); !errors.Is(err, zkx_errors.ErrMissingContext) {
+		t.Fatalf("proof without context: %v", err)
+	}
+
+	// The rejected attempts above did not burn the nonce
+	if err := z.VerifyContext(*login, signature, "service", nil, nonces); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Regression: the plain entry points must not accept a context-bound proof and skip its nonce
+func TestContextProofNeedsVerifyContext(t *testing.T) {
+	z := newTestZK(t)
+	nonces := NewMemoryNonceStore()
+	login, signature := contextLogin(t, z, nonces, "service")
+	if z.Verify(*login, signature, nil) {
+		t.Fatal("context-bound proof verifies without its context")
+	}
+	if z.Login(*login) {
+		t.Fatal("context-bound proof logs in without its context")
+	}
+	stripped := *login
+	stripped.Proof.Context = nil
+	if z.Verify(stripped, signature, nil) {
+		t.Fatal("proof verifies with its context stripped")
+	}
+}
+
+// Regression: a proof bound to one session must be refused on another before its nonce is spent
+func TestContextChannelBinding(t *testing.T) {
+	z := newTestZK(t)
+	nonces := NewMemoryNonceStore()
+	signature := z.CreateSignature(testSecret("alice"))
+	nonce, err := nonces.Issue(time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := []byte("fingerprint of the session of alice")
+	login := z.SignWithContext(testSecret("alice"), "login", NewProofContext("service", nonce, session, time.Minute))
+
+	for _, binding := range [][]byte{nil, []byte("fingerprint of the session of mallory")} {
+		if err := z.VerifyContext(*login, signature, "service", binding, nonces); !errors.Is(err, zkx_errors.ErrChannelBinding) {
+			t.Fatalf("proof for another session: %v", err)
+		}
+	}
+	rebound := *login
+	context := *login.Proof.Context
+	context.Binding = []byte("fingerprint of the session of mallory")
+	rebound.Proof.Context = &context
+	if err := z.VerifyContext(rebound, signature, "service", context.Binding, nonces); !errors.Is(err, zkx_errors.ErrInvalidProof) {
+		t.Fatalf("rebound proof: %v", err)
+	}
+	if err := z.VerifyContext(*login, signature, "service", session, nonces); err != nil {
+		t.Fatal(err)
+	}
+
+	unbound := z.SignWithContext(testSecret("alice"), "login", NewProofContext("service", nonce, nil, time.Minute))
+	if err := z.VerifyContext(*unbound, signature, "service", session, nonces); !errors.Is(err, zkx_errors.ErrChannelBinding) {
+		t.Fatalf("unbound proof on a bound session: %v", err)
+	}
+}
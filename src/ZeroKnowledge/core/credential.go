@@ -0,0 +1,292 @@
+package core
+
+import (
+	"errors"                                  // Import package for error handling
+	"fmt"                                     // Import package for formatted I/O
+	"math/big"                                // Import package for big integer arithmetic
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+	zkx_utils "tmp/src/ZeroKnowledge/utils"   // Import Zero Knowledge utility functions
+)
+
+// A credential is a batch of one-show commitments to the same attributes. The holder picks the blinding
+// of every commitment and proves it only touches the blinding generator, the issuer adds the attributes
+// and signs each commitment. Every presentation uses up one commitment, so no two presentations show
+// the same commitment or issuer proof and verifiers cannot link them.
+
+// maxCredentialCommitments bounds the commitments requested at once
+const maxCredentialCommitments = 1024
+
+// RequestCredential draws the blindings of count commitments to the attributes and returns the request for
+// the issuer together with the credential, which is usable once CompleteCredential added the issuer proofs
+func (z *ZeroKnowledge) RequestCredential(attributes []zkx_models.Attribute, count int) (*zkx_models.CredentialRequest, *zkx_models.Credential, error) {
+	if err := checkAttributes(attributes); err != nil {
+		return nil, nil, err
+	}
+	if count < 1 || count > maxCredentialCommitments {
+		return nil, nil, errors.New("Invalid number of credential commitments")
+	}
+	request := &zkx_models.CredentialRequest{Params: z.Params, Attributes: attributes}
+	credential := &zkx_models.Credential{Params: z.Params, Attributes: attributes}
+	for i := 0; i < count; i++ {
+		blinding, err := z.randomSecretScalar()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// B = r * H_0 and C = B + sum(a_i * H_i)
+		blinded := z.marshalPoint(z.secretMult(z.attributeBase(""), blinding))
+		commitment, err := z.credentialCommitment(blinded, attributes)
+		if err != nil {
+			return nil, nil, err
+		}
+		request.Blinded = append(request.Blinded, blinded)
+		request.Proofs = append(request.Proofs, z.createProof(z.attributeBase(""), blinding, blindingData(blinded)))
+		credential.Tokens = append(credential.Tokens, zkx_models.CredentialToken{
+			Blinding:   blinding.Bytes(),
+			Commitment: commitment,
+		})
+		blinding.Destroy()
+	}
+	return request, credential, nil
+}
+
+// IssueCredential adds the attributes to the blinded commitments of a request and signs them with the issuer secret
+func (z *ZeroKnowledge) IssueCredential(secret *zkx_types.SecretKey, request zkx_models.CredentialRequest) (*zkx_models.CredentialIssuance, error) {
+	if err := checkAttributes(request.Attributes); err != nil {
+		return nil, err
+	}
+	if len(request.Blinded) < 1 || len(request.Blinded) > maxCredentialCommitments || len(request.Proofs) != len(request.Blinded) {
+		return nil, errors.New("Malformed credential request")
+	}
+	issuance := &zkx_models.CredentialIssuance{Issuer: z.CreateSignature(secret)}
+	for i, blinded := range request.Blinded {
+		// A blinded point with attribute terms in it would let the holder open other values
+		if !z.verifyBaseProof(z.attributeBase(""), blinded, request.Proofs[i], blindingData(blinded)) {
+			return nil, errors.New("Invalid blinding proof")
+		}
+		commitment, err := z.credentialCommitment(blinded, request.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		issuance.Proofs = append(issuance.Proofs, z.CreateProof(secret, credentialData(commitment)))
+	}
+	return issuance, nil
+}
+
+// CompleteCredential checks the issuer proofs against the commitments of the credential and stores them
+func (z *ZeroKnowledge) CompleteCredential(credential *zkx_models.Credential, issuance zkx_models.CredentialIssuance) error {
+	if len(issuance.Proofs) != len(credential.Tokens) {
+		return errors.New("Issuance does not match the credential")
+	}
+	for i, token := range credential.Tokens {
+		if !z.Verify(issuance.Proofs[i], issuance.Issuer, credentialData(token.Commitment)) {
+			return errors.New("Invalid issuer proof")
+		}
+	}
+	for i := range credential.Tokens {
+		credential.Tokens[i].IssuerProof = issuance.Proofs[i]
+	}
+	credential.Issuer = issuance.Issuer
+	return nil
+}
+
+// PresentCredential reveals the named attributes and proves knowledge of the others, bound to the data, and
+// uses up one commitment of the credential
+func (z *ZeroKnowledge) PresentCredential(credential *zkx_models.Credential, disclose []string, data interface{}) (*zkx_models.CredentialPresentation, error) {
+	if len(credential.Tokens) == 0 {
+		return nil, errors.New("Credential has no unused commitment left")
+	}
+	token := credential.Tokens[0]
+	disclosing := make(map[string]bool)
+	for _, name := range disclose {
+		disclosing[name] = true
+	}
+	presentation := &zkx_models.CredentialPresentation{
+		Params:      z.Params,
+		Commitment:  token.Commitment,
+		Issuer:      credential.Issuer,
+		IssuerProof: token.IssuerProof,
+		Data:        fmt.Sprint(data),
+	}
+
+	// Split the attributes and collect the witnesses of the hidden ones
+	var witnesses []*zkx_types.Scalar
+	var bases []zkx_models.Point
+	for _, attribute := range credential.Attributes {
+		if disclosing[attribute.Name] {
+			presentation.Disclosed = append(presentation.Disclosed, attribute)
+			delete(disclosing, attribute.Name)
+			continue
+		}
+		presentation.Hidden = append(presentation.Hidden, attribute.Name)
+		witnesses = append(witnesses, z.attributeScalar(attribute))
+		bases = append(bases, z.attributeBase(attribute.Name))
+	}
+	if len(disclosing) != 0 {
+		return nil, errors.New("Credential lacks a disclosed attribute")
+	}
+	blinding, err := zkx_types.NewScalar(z.scalarOrder(), token.Blinding)
+	if err != nil {
+		return nil, err
+	}
+	witnesses = append(witnesses, blinding)
+	bases = append(bases, z.attributeBase(""))
+	defer func() {
+		for _, witness := range witnesses {
+			witness.Destroy()
+		}
+	}()
+
+	// Schnorr proof of representation of C' over the hidden bases
+	nonces := make([]*zkx_types.Scalar, len(witnesses))
+	var T zkx_models.Point
+	for i := range witnesses {
+		k, err := z.randomSecretScalar()
+		if err != nil {
+			return nil, err
+		}
+		defer k.Destroy()
+		nonces[i] = k
+		T = z.addPoints(T, z.secretMult(bases[i], k))
+	}
+	c := z.presentationChallenge(presentation, T)
+	cs := z.toScalar(c)
+	for i, witness := range witnesses {
+		s := new(zkx_types.Scalar).Sub(nonces[i], new(zkx_types.Scalar).Mul(cs, witness))
+		presentation.Responses = append(presentation.Responses, s.Bytes())
+	}
+	presentation.C = zkx_utils.IntToBytes(c)
+
+	// Never show the commitment again
+	wipe(credential.Tokens[0].Blinding)
+	credential.Tokens = credential.Tokens[1:]
+	return presentation, nil
+}
+
+// VerifyPresentation checks the issuer proof and the proof over the hidden attributes
+func (z *ZeroKnowledge) VerifyPresentation(presentation zkx_models.CredentialPresentation, issuer zkx_models.ZeroKnowledgeSignature, data interface{}) bool {
+	if presentation.Data != fmt.Sprint(data) || !sameSignature(presentation.Issuer, issuer) {
+		return false
+	}
+	if !z.Verify(presentation.IssuerProof, issuer, credentialData(presentation.Commitment)) {
+		return false
+	}
+	if len(presentation.Responses) != len(presentation.Hidden)+1 {
+		return false
+	}
+	commitment, err := z.unmarshalPoint(presentation.Commitment)
+	if err != nil {
+		return false
+	}
+
+	// C' = C - sum(a_i * H_i) over the disclosed attributes
+	names := make(map[string]bool)
+	zero := z.toScalar(new(big.Int))
+	for _, attribute := range presentation.Disclosed {
+		names[attribute.Name] = true
+		negated := new(zkx_types.Scalar).Sub(zero, z.attributeScalar(attribute))
+		commitment = z.addPoints(commitment, z.secretMult(z.attributeBase(attribute.Name), negated))
+	}
+	bases := make([]zkx_models.Point, 0, len(presentation.Responses))
+	for _, name := range presentation.Hidden {
+		if names[name] {
+			return false // An attribute cannot be both disclosed and hidden
+		}
+		names[name] = true
+		bases = append(bases, z.attributeBase(name))
+	}
+	bases = append(bases, z.attributeBase(""))
+
+	// T = sum(s_i * H_i) + c * C'
+	c := new(big.Int).SetBytes(presentation.C)
+	T := z.scalarMult(commitment, c)
+	for i, base := range bases {
+		T = z.addPoints(T, z.scalarMult(base, new(big.Int).SetBytes(presentation.Responses[i])))
+	}
+	return c.Cmp(z.presentationChallenge(&presentation, T)) == 0
+}
+
+// attributeBase returns the generator of a named attribute, the empty name is the blinding generator
+func (z *ZeroKnowledge) attributeBase(name string) zkx_models.Point {
+	x, y := zkx_utils.HashToCurve(z.Curve, []byte("Credential"), []byte(name))
+	return zkx_models.Point{X: x, Y: y}
+}
+
+// attributeScalar maps an attribute to the scalar committed for it
+func (z *ZeroKnowledge) attributeScalar(attribute zkx_models.Attribute) *zkx_types.Scalar {
+	return z.hashToScalar("Attribute", len(attribute.Name), attribute.Name, attribute.Value)
+}
+
+// addPoints adds two points, treating a point without coordinates as the identity
+func (z *ZeroKnowledge) addPoints(a zkx_models.Point, b zkx_models.Point) zkx_models.Point {
+	if a.X == nil {
+		return b
+	}
+	if b.X == nil {
+		return a
+	}
+	x, y := z.Curve.Add(a.X, a.Y, b.X, b.Y)
+	return zkx_models.Point{X: x, Y: y}
+}
+
+// presentationChallenge derives the Fiat-Shamir challenge of a presentation
+func (z *ZeroKnowledge) presentationChallenge(presentation *zkx_models.CredentialPresentation, T zkx_models.Point) *big.Int {
+	values := []interface{}{"Credential/Present", presentation.Commitment, presentation.Issuer.Signature, len(presentation.Issuer.Service), presentation.Issuer.Service, presentation.Data}
+	for _, attribute := range presentation.Disclosed {
+		values = append(values, len(attribute.Name), attribute.Name, len(attribute.Value), attribute.Value)
+	}
+	for _, name := range presentation.Hidden {
+		values = append(values, len(name), name)
+	}
+	return z.Hash(append(values, z.marshalPoint(T))...)
+}
+
+// credentialCommitment adds the attribute terms to a blinded point
+func (z *ZeroKnowledge) credentialCommitment(blinded []byte, attributes []zkx_models.Attribute) ([]byte, error) {
+	commitment, err := z.unmarshalPoint(blinded)
+	if err != nil {
+		return nil, err
+	}
+	for _, attribute := range attributes {
+		term := z.secretMult(z.attributeBase(attribute.Name), z.attributeScalar(attribute))
+		commitment = z.addPoints(commitment, term)
+	}
+	return z.marshalPoint(commitment), nil
+}
+
+// verifyBaseProof checks a proof made by createProof for the public point on the base
+func (z *ZeroKnowledge) verifyBaseProof(base zkx_models.Point, public []byte, proof zkx_models.ZeroKnowledgeProof, data string) bool {
+	point, err := z.unmarshalPoint(public)
+	if err != nil {
+		return false
+	}
+	c := new(big.Int).SetBytes(proof.C)
+	m := new(big.Int).SetBytes(proof.M)
+	if m.Cmp(z.Curve.Params().N) >= 0 {
+		return false
+	}
+	return c.Cmp(z.Hash(data, z.marshalPoint(z.commitment(base, point, c, m)))) == 0
+}
+
+// checkAttributes rejects attribute sets that name an attribute twice
+func checkAttributes(attributes []zkx_models.Attribute) error {
+	seen := make(map[string]bool)
+	for _, attribute := range attributes {
+		if seen[attribute.Name] {
+			return fmt.Errorf("Duplicate attribute %q", attribute.Name)
+		}
+		seen[attribute.Name] = true
+	}
+	return nil
+}
+
+// blindingData is the data the holder proves its blinding over
+func blindingData(blinded []byte) string {
+	return "Credential/Blinding/" + string(blinded)
+}
+
+// credentialData is the data an issuer proof is created over
+func credentialData(commitment []byte) string {
+	return "Credential/" + string(commitment)
+}
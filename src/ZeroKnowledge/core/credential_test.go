@@ -0,0 +1,168 @@
+package core
+
+import (
+	"bytes"                                   // Import package for byte slice comparison
+	"testing"                                 // Import package for testing
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+// testAttributes are the attributes of the test credentials
+var testAttributes = []zkx_models.Attribute{
+	{Name: "role", Value: "admin"},
+	{Name: "country", Value: "NL"},
+	{Name: "birthdate", Value: "1990-01-01"},
+}
+
+// testCredential runs the issuance of a credential with count commitments
+func testCredential(t *testing.T, z *ZeroKnowledge, count int) *zkx_models.Credential {
+	t.Helper()
+	request, credential, err := z.RequestCredential(testAttributes, count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := request.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := zkx_models.CredentialRequest{}
+	if err := decoded.FromJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	issuance, err := z.IssueCredential(testSecret("issuer"), decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := z.CompleteCredential(credential, *issuance); err != nil {
+		t.Fatal(err)
+	}
+	return credential
+}
+
+func TestCredentialRoundTrip(t *testing.T) {
+	z := newTestZK(t)
+	issuer := z.CreateSignature(testSecret("issuer"))
+	credential := testCredential(t, z, 2)
+	presentation, err := z.PresentCredential(credential, []string{"role"}, "session 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !z.VerifyPresentation(*presentation, issuer, "session 1") {
+		t.Fatal("presentation does not verify")
+	}
+	if len(presentation.Disclosed) != 1 || len(presentation.Hidden) != 2 {
+		t.Fatal("presentation disclosed the wrong attributes")
+	}
+	if z.VerifyPresentation(*presentation, issuer, "session 2") {
+		t.Fatal("presentation replays in another session")
+	}
+	if z.VerifyPresentation(*presentation, z.CreateSignature(testSecret("mallory")), "session 1") {
+		t.Fatal("presentation verifies against another issuer")
+	}
+}
+
+func TestCredentialPresentationsAreUnlinkable(t *testing.T) {
+	z := newTestZK(t)
+	issuer := z.CreateSignature(testSecret("issuer"))
+	credential := testCredential(t, z, 2)
+	first, err := z.PresentCredential(credential, []string{"role"}, "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := z.PresentCredential(credential, []string{"role"}, "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(first.Commitment, second.Commitment) || bytes.Equal(first.IssuerProof.C, second.IssuerProof.C) {
+		t.Fatal("two presentations share their commitment or issuer proof")
+	}
+	if !z.VerifyPresentation(*first, issuer, "data") || !z.VerifyPresentation(*second, issuer, "data") {
+		t.Fatal("presentation does not verify")
+	}
+	if _, err := z.PresentCredential(credential, []string{"role"}, "data"); err == nil {
+		t.Fatal("presented a credential with every commitment used up")
+	}
+}
+
+// Regression: the holder must pick the blinding, and a blinding with attribute terms must be refused
+func TestCredentialRequestBlinding(t *testing.T) {
+	z := newTestZK(t)
+	request, credential, err := z.RequestCredential(testAttributes, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuance, err := z.IssueCredential(testSecret("issuer"), *request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The issuer never sees the blinding, only its multiple of the blinding generator
+	if bytes.Contains(request.Blinded[0], credential.Tokens[0].Blinding) {
+		t.Fatal("request reveals the blinding")
+	}
+
+	// Shifting the blinded point by an attribute term changes the committed role
+	forged := *request
+	shift := z.secretMult(z.attributeBase("role"), z.attributeScalar(zkx_models.Attribute{Name: "role", Value: "root"}))
+	blinded, _ := z.unmarshalPoint(request.Blinded[0])
+	forged.Blinded = [][]byte{z.marshalPoint(z.addPoints(blinded, shift))}
+	if _, err := z.IssueCredential(testSecret("issuer"), forged); err == nil {
+		t.Fatal("issued over a blinding that is not a multiple of the blinding generator")
+	}
+
+	// Issuer proofs only complete the credential they were made for
+	other := testCredential(t, z, 1)
+	if err := z.CompleteCredential(other, *issuance); err == nil {
+		t.Fatal("completed a credential with the proofs of another")
+	}
+	if err := z.CompleteCredential(credential, *issuance); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCredentialRejectsTamperedPresentation(t *testing.T) {
+	z := newTestZK(t)
+	issuer := z.CreateSignature(testSecret("issuer"))
+	credential := testCredential(t, z, 1)
+	presentation, err := z.PresentCredential(credential, []string{"role", "country"}, "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forged := *presentation
+	forged.Disclosed = []zkx_models.Attribute{{Name: "role", Value: "root"}, presentation.Disclosed[1]}
+	if z.VerifyPresentation(forged, issuer, "data") {
+		t.Fatal("presentation verifies with a changed attribute value")
+	}
+	forged = *presentation
+	forged.Hidden = append([]string{"role"}, presentation.Hidden...)
+	forged.Responses = append([][]byte{presentation.Responses[0]}, presentation.Responses...)
+	if z.VerifyPresentation(forged, issuer, "data") {
+		t.Fatal("presentation verifies with an attribute both disclosed and hidden")
+	}
+	forged = *presentation
+	forged.Responses = presentation.Responses[:1]
+	if z.VerifyPresentation(forged, issuer, "data") {
+		t.Fatal("presentation verifies with a missing response")
+	}
+	forged = *presentation
+	forged.Commitment = testCredential(t, z, 1).Tokens[0].Commitment
+	if z.VerifyPresentation(forged, issuer, "data") {
+		t.Fatal("presentation verifies over another commitment")
+	}
+}
+
+func TestCredentialRejectsMalformedInput(t *testing.T) {
+	z := newTestZK(t)
+	if _, _, err := z.RequestCredential([]zkx_models.Attribute{{Name: "role", Value: "a"}, {Name: "role", Value: "b"}}, 1); err == nil {
+		t.Fatal("credential requested with a duplicated attribute")
+	}
+	if _, _, err := z.RequestCredential(testAttributes, 0); err == nil {
+		t.Fatal("credential requested without commitments")
+	}
+	if _, err := z.IssueCredential(testSecret("issuer"), zkx_models.CredentialRequest{Attributes: testAttributes}); err == nil {
+		t.Fatal("credential issued for an empty request")
+	}
+	if _, err := z.PresentCredential(testCredential(t, z, 1), []string{"email"}, "data"); err == nil {
+		t.Fatal("presentation discloses an attribute the credential lacks")
+	}
+}
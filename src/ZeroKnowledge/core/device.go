@@ -0,0 +1,112 @@
+package core
+
+import (
+	"encoding/json"                           // Import package for JSON encoding and decoding
+	"errors"                                  // Import package for error handling
+	"fmt"                                     // Import package for formatted I/O
+	"time"                                    // Import package for handling time
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+)
+
+// EnrollFirstDevice registers the first device of an account, which needs no voucher
+func (z *ZeroKnowledge) EnrollFirstDevice(registry DeviceRegistry, user string, deviceID string, signature zkx_models.ZeroKnowledgeSignature) error {
+	if len(registry.Devices(user)) != 0 {
+		return errors.New("Account already has devices")
+	}
+	return registry.AddDevice(user, zkx_models.DeviceKey{
+		ID:        deviceID,
+		Signature: signature,
+		Enrolled:  time.Now().UTC(),
+	})
+}
+
+// CreateEnrollment lets an existing device vouch for a new one, both proofs are bound to the data
+func (z *ZeroKnowledge) CreateEnrollment(user string, enrollerID string, enrollerSecret *zkx_types.SecretKey, deviceID string, deviceSecret *zkx_types.SecretKey, data interface{}) (*zkx_models.DeviceEnrollment, error) {
+	signature := z.CreateSignature(deviceSecret)
+	payload := fmt.Sprint(data)
+	transcript, err := enrollmentTranscript(user, enrollerID, deviceID, signature, payload)
+	if err != nil {
+		return nil, err
+	}
+	return &zkx_models.DeviceEnrollment{
+		DeviceID:      deviceID,
+		Signature:     signature,
+		Enroller:      enrollerID,
+		Data:          payload,
+		EnrollerProof: z.CreateProof(enrollerSecret, transcript),
+		DeviceProof:   z.CreateProof(deviceSecret, transcript),
+	}, nil
+}
+
+// EnrollDevice verifies an enrollment against the active devices of the account and stores the new device
+func (z *ZeroKnowledge) EnrollDevice(registry DeviceRegistry, user string, enrollment zkx_models.DeviceEnrollment, data interface{}) error {
+	if enrollment.Data != fmt.Sprint(data) {
+		return errors.New("Enrollment is bound to other data")
+	}
+	enroller, ok := activeDevice(registry, user, enrollment.Enroller)
+	if !ok {
+		return errors.New("Enrolling device is unknown or revoked")
+	}
+	transcript, err := enrollmentTranscript(user, enrollment.Enroller, enrollment.DeviceID, enrollment.Signature, enrollment.Data)
+	if err != nil {
+		return err
+	}
+	if !z.Verify(enrollment.EnrollerProof, enroller.Signature, transcript) {
+		return errors.New("Invalid enrolling device proof")
+	}
+	if !z.Verify(enrollment.DeviceProof, enrollment.Signature, transcript) {
+		return errors.New("Invalid new device proof")
+	}
+	return registry.AddDevice(user, zkx_models.DeviceKey{
+		ID:         enrollment.DeviceID,
+		Signature:  enrollment.Signature,
+		EnrolledBy: enrollment.Enroller,
+		Enrolled:   time.Now().UTC(),
+	})
+}
+
+// LoginDevice performs a login with an active device of the user and reports which device was used, the
+// token must be issued by GenerateUserJWT for the user and the signature of that device
+func (z *ZeroKnowledge) LoginDevice(registry DeviceRegistry, user string, loginData zkx_models.ZeroKnowledgeData) (string, error) {
+	claims, err := z.verifyJWT([]byte(loginData.Data))
+	if err != nil {
+		return "", err
+	}
+	if subject, _ := claims["sub"].(string); subject != user {
+		return "", errors.New("Token was issued for another user")
+	}
+	signature, err := jwtSignature(claims)
+	if err != nil {
+		return "", err
+	}
+	for _, device := range registry.Devices(user) {
+		if device.Revoked || !sameSignature(device.Signature, signature) {
+			continue
+		}
+		if !z.Verify(loginData, device.Signature, nil) {
+			return "", errors.New("Invalid device proof")
+		}
+		return device.ID, nil
+	}
+	return "", errors.New("No active device matches the token")
+}
+
+// activeDevice looks up a device of the user that has not been revoked
+func activeDevice(registry DeviceRegistry, user string, deviceID string) (zkx_models.DeviceKey, bool) {
+	for _, device := range registry.Devices(user) {
+		if device.ID == deviceID && !device.Revoked {
+			return device, true
+		}
+	}
+	return zkx_models.DeviceKey{}, false
+}
+
+// enrollmentTranscript encodes everything an enrollment proof commits to
+func enrollmentTranscript(user string, enrollerID string, deviceID string, signature zkx_models.ZeroKnowledgeSignature, data string) (string, error) {
+	transcript, err := json.Marshal([]interface{}{"DeviceEnrollment", user, enrollerID, deviceID, signature.Signature, data})
+	if err != nil {
+		return "", err
+	}
+	return string(transcript), nil
+}
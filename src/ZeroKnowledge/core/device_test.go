@@ -0,0 +1,105 @@
+package core
+
+import (
+	"testing"                                 // Import package for testing
+	"time"                                    // Import package for handling time
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+// deviceLogin signs a fresh token of the user and the device secret
+func deviceLogin(t *testing.T, z *ZeroKnowledge, user string, device string) zkx_models.ZeroKnowledgeData {
+	t.Helper()
+	token, err := z.GenerateUserJWT(user, z.CreateSignature(testSecret(device)), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *z.Sign(testSecret(device), token)
+}
+
+func TestDeviceEnrollment(t *testing.T) {
+	z := newTestZK(t)
+	registry := NewMemoryRegistry()
+	if err := z.EnrollFirstDevice(registry, "alice", "laptop", z.CreateSignature(testSecret("laptop"))); err != nil {
+		t.Fatal(err)
+	}
+	enrollment, err := z.CreateEnrollment("alice", "laptop", testSecret("laptop"), "phone", testSecret("phone"), "challenge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := enrollment.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := zkx_models.DeviceEnrollment{}
+	if err := decoded.FromJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.EnrollDevice(registry, "alice", decoded, "other challenge"); err == nil {
+		t.Fatal("enrollment over other data was accepted")
+	}
+	if err := z.EnrollDevice(registry, "bob", decoded, "challenge"); err == nil {
+		t.Fatal("enrollment for another account was accepted")
+	}
+	if err := z.EnrollDevice(registry, "alice", decoded, "challenge"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A stranger cannot vouch for a device of the account
+	forged, err := z.CreateEnrollment("alice", "laptop", testSecret("mallory"), "tablet", testSecret("tablet"), "challenge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := z.EnrollDevice(registry, "alice", *forged, "challenge"); err == nil {
+		t.Fatal("enrollment vouched by a stranger was accepted")
+	}
+}
+
+func TestDeviceLogin(t *testing.T) {
+	z := newTestZK(t)
+	registry := NewMemoryRegistry()
+	if err := z.EnrollFirstDevice(registry, "alice", "laptop", z.CreateSignature(testSecret("laptop"))); err != nil {
+		t.Fatal(err)
+	}
+	enrollment, err := z.CreateEnrollment("alice", "laptop", testSecret("laptop"), "phone", testSecret("phone"), "challenge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := z.EnrollDevice(registry, "alice", *enrollment, "challenge"); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.EnrollFirstDevice(registry, "bob", "desktop", z.CreateSignature(testSecret("desktop"))); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, device := range []string{"laptop", "phone"} {
+		used, err := z.LoginDevice(registry, "alice", deviceLogin(t, z, "alice", device))
+		if err != nil || used != device {
+			t.Fatalf("login with %s reported %q, %v", device, used, err)
+		}
+	}
+
+	// The token must name the user and the device the proof comes from
+	if _, err := z.LoginDevice(registry, "alice", deviceLogin(t, z, "bob", "desktop")); err == nil {
+		t.Fatal("token of another user logged in")
+	}
+	if _, err := z.LoginDevice(registry, "alice", deviceLogin(t, z, "alice", "desktop")); err == nil {
+		t.Fatal("device of another account logged in")
+	}
+	token, err := z.GenerateUserJWT("alice", z.CreateSignature(testSecret("laptop")), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := z.LoginDevice(registry, "alice", *z.Sign(testSecret("phone"), token)); err == nil {
+		t.Fatal("proof of one device logged in with the token of another")
+	}
+
+	if err := registry.RevokeDevice("alice", "phone"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := z.LoginDevice(registry, "alice", deviceLogin(t, z, "alice", "phone")); err == nil {
+		t.Fatal("revoked device logged in")
+	}
+	if _, err := z.LoginDevice(registry, "alice", deviceLogin(t, z, "alice", "laptop")); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,206 @@
+package core
+
+import (
+	"bytes"                                   // Import package for byte slice comparison
+	"errors"                                  // Import package for error handling
+	"math"                                    // Import package for square roots
+	"math/big"                                // Import package for big integer arithmetic
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+	zkx_utils "tmp/src/ZeroKnowledge/utils"   // Import Zero Knowledge utility functions
+)
+
+// Exponential ElGamal encrypts v to the point Y = x*G of a signature as (r*G, v*G + r*Y). Ciphertexts
+// add up to an encryption of the sum, and decrypting yields v*G, from which small values are recovered
+// with a bounded discrete logarithm.
+
+// MaxDiscreteLogBound is the largest bound SolveDiscreteLog accepts, its table grows with the square root
+const MaxDiscreteLogBound = 1 << 40
+
+// Encrypt encrypts a small non-negative value to the point of the recipient signature
+func (z *ZeroKnowledge) Encrypt(recipient zkx_models.ZeroKnowledgeSignature, value int64) (*zkx_models.Ciphertext, error) {
+	if value < 0 {
+		return nil, errors.New("Value must not be negative")
+	}
+	public, err := z.elgamalPoint(recipient)
+	if err != nil {
+		return nil, err
+	}
+	r, err := z.randomSecretScalar()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Destroy()
+	vx, vy := z.Curve.ScalarBaseMult(big.NewInt(value).Bytes())
+	ry := z.secretMult(public, r)
+	bx, by := z.Curve.Add(vx, vy, ry.X, ry.Y)
+	return &zkx_models.Ciphertext{
+		Params:    z.Params,
+		Recipient: recipient,
+		A:         z.marshalPoint(z.secretBaseMult(r)),
+		B:         z.marshalPoint(zkx_models.Point{X: bx, Y: by}),
+	}, nil
+}
+
+// AddCiphertexts adds two ciphertexts to the same recipient into an encryption of the sum of their values
+func (z *ZeroKnowledge) AddCiphertexts(first zkx_models.Ciphertext, second zkx_models.Ciphertext) (*zkx_models.Ciphertext, error) {
+	if !bytes.Equal(first.Recipient.Signature, second.Recipient.Signature) {
+		return nil, errors.New("Ciphertexts are encrypted to different recipients")
+	}
+	a1, b1, err := z.ciphertextPoints(first)
+	if err != nil {
+		return nil, err
+	}
+	a2, b2, err := z.ciphertextPoints(second)
+	if err != nil {
+		return nil, err
+	}
+	ax, ay := z.Curve.Add(a1.X, a1.Y, a2.X, a2.Y)
+	bx, by := z.Curve.Add(b1.X, b1.Y, b2.X, b2.Y)
+	return &zkx_models.Ciphertext{
+		Params:    z.Params,
+		Recipient: first.Recipient,
+		A:         z.marshalPoint(zkx_models.Point{X: ax, Y: ay}),
+		B:         z.marshalPoint(zkx_models.Point{X: bx, Y: by}),
+	}, nil
+}
+
+// Rerandomize returns a fresh-looking ciphertext of the same value that cannot be linked to the original
+func (z *ZeroKnowledge) Rerandomize(ciphertext zkx_models.Ciphertext) (*zkx_models.Ciphertext, error) {
+	zero, err := z.Encrypt(ciphertext.Recipient, 0)
+	if err != nil {
+		return nil, err
+	}
+	return z.AddCiphertexts(ciphertext, *zero)
+}
+
+// Decrypt decrypts a ciphertext whose value is at most bound and proves the decryption correct
+func (z *ZeroKnowledge) Decrypt(secret *zkx_types.SecretKey, ciphertext zkx_models.Ciphertext, bound int64) (*zkx_models.Decryption, error) {
+	key := z.secretScalar(secret)
+	defer key.Destroy()
+	public := z.secretBaseMult(key)
+	if !bytes.Equal(z.marshalPoint(public), ciphertext.Recipient.Signature) {
+		return nil, errors.New("Secret does not match the recipient")
+	}
+	a, b, err := z.ciphertextPoints(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	// v*G = B - x*A
+	share := z.secretMult(a, key)
+	value, err := z.SolveDiscreteLog(z.subPoints(b, share), bound)
+	if err != nil {
+		return nil, err
+	}
+
+	// Chaum-Pedersen proof that log_G(Y) == log_A(x*A)
+	r, err := z.randomSecretScalar()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Destroy()
+	c := z.decryptionChallenge(ciphertext, share, z.secretBaseMult(r), z.secretMult(a, r))
+	m := new(zkx_types.Scalar).Sub(r, new(zkx_types.Scalar).Mul(z.toScalar(c), key))
+
+	return &zkx_models.Decryption{
+		Params: z.Params,
+		Value:  value,
+		Share:  z.marshalPoint(share),
+		C:      zkx_utils.IntToBytes(c),
+		M:      m.Bytes(),
+	}, nil
+}
+
+// VerifyDecryption checks that the value was decrypted from the ciphertext with the key of the recipient
+func (z *ZeroKnowledge) VerifyDecryption(ciphertext zkx_models.Ciphertext, decryption zkx_models.Decryption) bool {
+	if decryption.Value < 0 {
+		return false
+	}
+	public, err := z.elgamalPoint(ciphertext.Recipient)
+	if err != nil {
+		return false
+	}
+	a, b, err := z.ciphertextPoints(ciphertext)
+	if err != nil {
+		return false
+	}
+	share, err := z.unmarshalPoint(decryption.Share)
+	if err != nil {
+		return false
+	}
+	c := new(big.Int).SetBytes(decryption.C)
+	m := new(big.Int).SetBytes(decryption.M)
+	a1 := z.commitment(z.basePoint(""), public, c, m)
+	a2 := z.commitment(a, share, c, m)
+	if c.Cmp(z.decryptionChallenge(ciphertext, share, a1, a2)) != 0 {
+		return false
+	}
+	vx, vy := z.Curve.ScalarBaseMult(big.NewInt(decryption.Value).Bytes())
+	return bytes.Equal(z.marshalPoint(z.subPoints(b, share)), z.marshalPoint(zkx_models.Point{X: vx, Y: vy}))
+}
+
+// SolveDiscreteLog finds v in [0, bound] with v*G equal to the point, using baby-step giant-step
+func (z *ZeroKnowledge) SolveDiscreteLog(point zkx_models.Point, bound int64) (int64, error) {
+	if bound < 0 || bound > MaxDiscreteLogBound {
+		return 0, errors.New("Discrete logarithm bound out of range")
+	}
+	steps := int64(math.Ceil(math.Sqrt(float64(bound + 1))))
+
+	// Baby steps j*G for j < steps, the identity is (0, 0)
+	table := make(map[string]int64, steps)
+	current := zkx_models.Point{X: new(big.Int), Y: new(big.Int)}
+	generator := z.basePoint("")
+	for j := int64(0); j < steps; j++ {
+		table[string(z.marshalPoint(current))] = j
+		x, y := z.Curve.Add(current.X, current.Y, generator.X, generator.Y)
+		current = zkx_models.Point{X: x, Y: y}
+	}
+
+	// Giant steps subtract steps*G until a baby step matches
+	sx, sy := z.Curve.ScalarBaseMult(big.NewInt(steps).Bytes())
+	giant := zkx_models.Point{X: sx, Y: sy}
+	current = point
+	for i := int64(0); i*steps <= bound; i++ {
+		if j, ok := table[string(z.marshalPoint(current))]; ok && i*steps+j <= bound {
+			return i*steps + j, nil
+		}
+		current = z.subPoints(current, giant)
+	}
+	return 0, errors.New("Value exceeds the discrete logarithm bound")
+}
+
+// elgamalPoint decodes the point of a recipient, values are only encrypted to master signatures
+func (z *ZeroKnowledge) elgamalPoint(recipient zkx_models.ZeroKnowledgeSignature) (zkx_models.Point, error) {
+	if recipient.Service != "" {
+		return zkx_models.Point{}, errors.New("Recipient must be a master signature")
+	}
+	return z.unmarshalPoint(recipient.Signature)
+}
+
+// ciphertextPoints decodes both points of a ciphertext
+func (z *ZeroKnowledge) ciphertextPoints(ciphertext zkx_models.Ciphertext) (zkx_models.Point, zkx_models.Point, error) {
+	a, err := z.unmarshalPoint(ciphertext.A)
+	if err != nil {
+		return zkx_models.Point{}, zkx_models.Point{}, err
+	}
+	b, err := z.unmarshalPoint(ciphertext.B)
+	if err != nil {
+		return zkx_models.Point{}, zkx_models.Point{}, err
+	}
+	return a, b, nil
+}
+
+// subPoints computes a - b
+func (z *ZeroKnowledge) subPoints(a zkx_models.Point, b zkx_models.Point) zkx_models.Point {
+	negated := new(big.Int).Sub(z.Curve.Params().P, b.Y)
+	negated.Mod(negated, z.Curve.Params().P)
+	x, y := z.Curve.Add(a.X, a.Y, b.X, negated)
+	return zkx_models.Point{X: x, Y: y}
+}
+
+// decryptionChallenge derives the Fiat-Shamir challenge of a decryption proof
+func (z *ZeroKnowledge) decryptionChallenge(ciphertext zkx_models.Ciphertext, share zkx_models.Point, a1 zkx_models.Point, a2 zkx_models.Point) *big.Int {
+	return z.Hash("ElGamal/Decrypt", ciphertext.Recipient.Signature, ciphertext.A, ciphertext.B,
+		z.marshalPoint(share), z.marshalPoint(a1), z.marshalPoint(a2))
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"testing" // Import package for testing
+)
+
+func TestElGamalRoundTrip(t *testing.T) {
+	z := newTestZK(t)
+	secret := testSecret("alice")
+	recipient := z.CreateSignature(secret)
+	first, err := z.Encrypt(recipient, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := z.Encrypt(recipient, 22)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := z.AddCiphertexts(*first, *second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err = z.Rerandomize(*sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decryption, err := z.Decrypt(secret, *sum, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decryption.Value != 42 {
+		t.Fatalf("decrypted %d", decryption.Value)
+	}
+	if !z.VerifyDecryption(*sum, *decryption) {
+		t.Fatal("decryption proof does not verify")
+	}
+
+	zero, err := z.Encrypt(recipient, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decryption, err = z.Decrypt(secret, *zero, 10)
+	if err != nil || decryption.Value != 0 || !z.VerifyDecryption(*zero, *decryption) {
+		t.Fatalf("zero decrypted to %v, %v", decryption, err)
+	}
+}
+
+func TestElGamalRejectsTampering(t *testing.T) {
+	z := newTestZK(t)
+	secret := testSecret("alice")
+	recipient := z.CreateSignature(secret)
+	ciphertext, err := z.Encrypt(recipient, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decryption, err := z.Decrypt(secret, *ciphertext, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forged := *decryption
+	forged.Value = 8
+	if z.VerifyDecryption(*ciphertext, forged) {
+		t.Fatal("decryption verifies with another value")
+	}
+	forged = *decryption
+	forged.M = append([]byte(nil), decryption.M...)
+	forged.M[0] ^= 1
+	if z.VerifyDecryption(*ciphertext, forged) {
+		t.Fatal("decryption verifies with a tampered proof")
+	}
+	other, err := z.Encrypt(recipient, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if z.VerifyDecryption(*other, *decryption) {
+		t.Fatal("decryption verifies for another ciphertext")
+	}
+
+	if _, err := z.Decrypt(testSecret("mallory"), *ciphertext, 100); err == nil {
+		t.Fatal("decrypted with the secret of another user")
+	}
+	if _, err := z.Decrypt(secret, *ciphertext, 5); err == nil {
+		t.Fatal("decrypted a value above the bound")
+	}
+	if _, err := z.Encrypt(recipient, -1); err == nil {
+		t.Fatal("encrypted a negative value")
+	}
+	foreign, err := z.Encrypt(z.CreateSignature(testSecret("bob")), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := z.AddCiphertexts(*ciphertext, *foreign); err == nil {
+		t.Fatal("added ciphertexts of different recipients")
+	}
+	malformed := *ciphertext
+	malformed.A = malformed.A[1:]
+	if _, err := z.Decrypt(secret, malformed, 100); err == nil {
+		t.Fatal("decrypted a malformed ciphertext")
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !unix
+
+package core
+
+import (
+	"errors" // Import package for error handling
+	"os"     // Import package for file access
+)
+
+// lockFile fails where no file lock is available, sharing the state unlocked could reuse one-time keys
+func lockFile(path string) (*os.File, error) {
+	return nil, errors.New("File locks are not supported on this platform")
+}
+
+// unlockFile releases a lock taken with lockFile
+func unlockFile(file *os.File) error {
+	return file.Close()
+}
@@ -0,0 +1,30 @@
+//go:build unix
+
+package core
+
+import (
+	"os"      // Import package for file access
+	"syscall" // Import package for file locks
+)
+
+// lockFile opens the lock file at path and blocks until this process holds its exclusive lock
+func lockFile(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// unlockFile releases a lock taken with lockFile
+func unlockFile(file *os.File) error {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
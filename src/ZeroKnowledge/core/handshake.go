@@ -0,0 +1,77 @@
+package core
+
+import (
+	"bytes"                                   // Import package for byte slice comparison
+	"encoding/json"                           // Import package for JSON encoding and decoding
+	"time"                                    // Import package for handling time
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+	zkx_utils "tmp/src/ZeroKnowledge/utils"   // Import Zero Knowledge utility functions
+)
+
+// A mutual login runs in three messages. The client sends a random challenge, the server answers
+// with its signature, the login token, its audience, a fresh nonce and a proof over all of them, and the
+// client checks that signature against its pinned copy before it sends a login proof bound to the nonce.
+
+// handshakeChallengeSize is the number of random bytes in a client challenge
+const handshakeChallengeSize = 32
+
+// NewClientHello starts a mutual login on the client side
+func NewClientHello() *zkx_models.ClientHello {
+	return &zkx_models.ClientHello{Challenge: zkx_utils.GenerateSalt(handshakeChallengeSize)}
+}
+
+// NewServerHello proves the identity of the server over the client challenge, the login token and the audience,
+// and issues the login nonce
+func (z *ZeroKnowledge) NewServerHello(secret *zkx_types.SecretKey, hello zkx_models.ClientHello, token string, audience string, nonces NonceStore, lifetime time.Duration) (*zkx_models.ServerHello, error) {
+	nonce, err := nonces.Issue(time.Now().Add(lifetime))
+	if err != nil {
+		return nil, err
+	}
+	server := zkx_models.ServerHello{
+		Signature: z.CreateSignature(secret),
+		Token:     token,
+		Audience:  audience,
+		Nonce:     nonce,
+	}
+	server.Proof = z.CreateProof(secret, handshakeTranscript(hello, server))
+	return &server, nil
+}
+
+// VerifyServerHello checks that the answer comes from the pinned server and covers our challenge
+func (z *ZeroKnowledge) VerifyServerHello(pinned zkx_models.ZeroKnowledgeSignature, hello zkx_models.ClientHello, server zkx_models.ServerHello) error {
+	if !bytes.Equal(server.Signature.Signature, pinned.Signature) || server.Signature.Service != pinned.Service {
+		return zkx_errors.ErrServerNotPinned
+	}
+	if !z.Verify(server.Proof, pinned, handshakeTranscript(hello, server)) {
+		return zkx_errors.ErrServerProof
+	}
+	return nil
+}
+
+// MutualLogin verifies the server and its audience and only then creates the login proof over the token of the
+// server, bound to the audience and to the nonce of the server. The server checks the result with LoginWithContext.
+func (z *ZeroKnowledge) MutualLogin(secret *zkx_types.SecretKey, pinned zkx_models.ZeroKnowledgeSignature, hello zkx_models.ClientHello, server zkx_models.ServerHello, audience string, lifetime time.Duration) (*zkx_models.ZeroKnowledgeData, error) {
+	if err := z.VerifyServerHello(pinned, hello, server); err != nil {
+		return nil, err
+	}
+	if server.Audience != audience {
+		return nil, zkx_errors.ErrWrongAudience
+	}
+	return z.SignWithContext(secret, server.Token, NewProofContext(audience, server.Nonce, nil, lifetime)), nil
+}
+
+// handshakeTranscript encodes what the proof of the server commits to
+func handshakeTranscript(hello zkx_models.ClientHello, server zkx_models.ServerHello) string {
+	transcript, _ := json.Marshal([]interface{}{
+		"MutualLogin/Server",
+		hello.Challenge,
+		server.Signature.Signature,
+		server.Signature.Service,
+		server.Token,
+		server.Audience,
+		server.Nonce,
+	})
+	return string(transcript)
+}
@@ -0,0 +1,84 @@
+package core
+
+import (
+	"errors"                                  // Import package for error handling
+	"testing"                                 // Import package for testing
+	"time"                                    // Import package for handling time
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+// serverHello answers a fresh client hello with a token for alice
+func serverHello(t *testing.T, z *ZeroKnowledge, nonces NonceStore) (*zkx_models.ClientHello, *zkx_models.ServerHello) {
+	t.Helper()
+	hello := NewClientHello()
+	token, err := z.GenerateJWT(z.CreateSignature(testSecret("alice")), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := z.NewServerHello(testSecret("server"), *hello, token, "service", nonces, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hello, server
+}
+
+func TestMutualLogin(t *testing.T) {
+	z := newTestZK(t)
+	nonces := NewMemoryNonceStore()
+	pinned := z.CreateSignature(testSecret("server"))
+	hello, server := serverHello(t, z, nonces)
+	login, err := z.MutualLogin(testSecret("alice"), pinned, *hello, *server, "service", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if login.Data != server.Token {
+		t.Fatal("login proof is not over the token of the server")
+	}
+	if err := z.LoginWithContext(*login, "service", nil, nonces); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.LoginWithContext(*login, "service", nil, nonces); !errors.Is(err, zkx_errors.ErrNonceReused) {
+		t.Fatalf("replayed login: %v", err)
+	}
+}
+
+func TestMutualLoginRejectsServer(t *testing.T) {
+	z := newTestZK(t)
+	nonces := NewMemoryNonceStore()
+	pinned := z.CreateSignature(testSecret("server"))
+	hello, server := serverHello(t, z, nonces)
+
+	if _, err := z.MutualLogin(testSecret("alice"), z.CreateSignature(testSecret("mallory")), *hello, *server, "service", time.Minute); !errors.Is(err, zkx_errors.ErrServerNotPinned) {
+		t.Fatalf("unpinned server: %v", err)
+	}
+	if _, err := z.MutualLogin(testSecret("alice"), pinned, *NewClientHello(), *server, "service", time.Minute); !errors.Is(err, zkx_errors.ErrServerProof) {
+		t.Fatalf("answer to another challenge: %v", err)
+	}
+	if _, err := z.MutualLogin(testSecret("alice"), pinned, *hello, *server, "other service", time.Minute); !errors.Is(err, zkx_errors.ErrWrongAudience) {
+		t.Fatalf("server for another audience: %v", err)
+	}
+}
+
+// Regression: the proof of the server must cover the token and the audience it hands out
+func TestMutualLoginRejectsSwappedToken(t *testing.T) {
+	z := newTestZK(t)
+	nonces := NewMemoryNonceStore()
+	pinned := z.CreateSignature(testSecret("server"))
+	hello, server := serverHello(t, z, nonces)
+
+	swapped := *server
+	token, err := z.GenerateJWT(z.CreateSignature(testSecret("mallory")), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	swapped.Token = token
+	if _, err := z.MutualLogin(testSecret("alice"), pinned, *hello, swapped, "service", time.Minute); !errors.Is(err, zkx_errors.ErrServerProof) {
+		t.Fatalf("swapped token: %v", err)
+	}
+	swapped = *server
+	swapped.Audience = "other service"
+	if _, err := z.MutualLogin(testSecret("alice"), pinned, *hello, swapped, "other service", time.Minute); !errors.Is(err, zkx_errors.ErrServerProof) {
+		t.Fatalf("swapped audience: %v", err)
+	}
+}
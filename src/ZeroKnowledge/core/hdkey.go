@@ -0,0 +1,270 @@
+package core
+
+import (
+	"bytes"                                   // Import package for byte slice comparison
+	"crypto/elliptic"                         // Import elliptic curve functions
+	"crypto/hmac"                             // Import package for HMAC construction
+	"crypto/sha256"                           // Import SHA-256 cryptographic hash function
+	"crypto/sha512"                           // Import SHA-512 cryptographic hash function
+	"encoding/base64"                         // Import package for base64 encoding
+	"encoding/binary"                         // Import package for big-endian integers
+	"errors"                                  // Import package for error handling
+	"fmt"                                     // Import package for formatted I/O
+	"math"                                    // Import package for integer limits
+	"math/big"                                // Import package for big integer arithmetic
+	"strconv"                                 // Import package for parsing path indices
+	"strings"                                 // Import package for string manipulation
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+)
+
+// Derivation follows BIP32 on the curve of the instance. A child of index i uses
+// HMAC-SHA512(chain code, data || i), where data is the parent scalar for hardened indices and the
+// compressed parent point otherwise, and adds the left half to the parent key. Non-hardened children
+// of a public key can therefore be derived without any secret.
+
+// HardenedOffset is added to an index to derive a hardened child
+const HardenedOffset uint32 = 1 << 31
+
+// extendedKeySize is the size of a serialized extended key before encoding
+const extendedKeySize = 4 + 1 + 4 + 4 + 32 + 33
+
+// Version prefixes of serialized extended keys
+var (
+	extendedPrivateVersion = []byte("ZKXV") // Prefix of private extended keys
+	extendedPublicVersion  = []byte("ZKXP") // Prefix of public extended keys
+)
+
+// MasterKey derives the root of the identity tree of a secret, its point is the signature of the secret
+func (z *ZeroKnowledge) MasterKey(secret *zkx_types.SecretKey) *zkx_models.ExtendedKey {
+	mac := hmac.New(sha512.New, []byte("ZeroKnowledge/ChainCode"))
+	mac.Write(secret.Bytes())
+	sum := mac.Sum(nil)
+	key := z.secretScalar(secret)
+	return &zkx_models.ExtendedKey{
+		Params:            z.Params,
+		ParentFingerprint: make([]byte, 4),
+		ChainCode:         sum[32:],
+		Key:               key,
+		Public:            z.marshalPoint(z.secretBaseMult(key)),
+	}
+}
+
+// DeriveChild derives the child at index, public parents can only derive non-hardened children
+func (z *ZeroKnowledge) DeriveChild(parent zkx_models.ExtendedKey, index uint32) (*zkx_models.ExtendedKey, error) {
+	if parent.Depth == math.MaxUint8 {
+		return nil, errors.New("Extended key is at the maximum depth") // The depth byte would wrap around to the master key
+	}
+	point, err := z.unmarshalPoint(parent.Public)
+	if err != nil {
+		return nil, err
+	}
+	compressed := elliptic.MarshalCompressed(z.Curve, point.X, point.Y)
+
+	mac := hmac.New(sha512.New, parent.ChainCode)
+	if index >= HardenedOffset {
+		if !parent.IsPrivate() {
+			return nil, errors.New("Hardened child of a public key")
+		}
+		mac.Write([]byte{0x00})
+		mac.Write(parent.Key.Bytes())
+	} else {
+		mac.Write(compressed)
+	}
+	mac.Write(binary.BigEndian.AppendUint32(nil, index))
+	sum := mac.Sum(nil)
+	defer wipe(sum)
+
+	// BIP32 skips indices whose tweak is not a valid scalar, this happens with negligible probability
+	if new(big.Int).SetBytes(sum[:32]).Cmp(z.Curve.Params().N) >= 0 {
+		return nil, errors.New("Invalid child index, use the next one")
+	}
+	tweak, err := zkx_types.NewScalar(z.scalarOrder(), sum[:32])
+	if err != nil {
+		return nil, err
+	}
+	defer tweak.Destroy()
+
+	fingerprint := sha256.Sum256(compressed)
+	child := &zkx_models.ExtendedKey{
+		Params:            parent.Params,
+		Depth:             parent.Depth + 1,
+		ParentFingerprint: fingerprint[:4],
+		Index:             index,
+		ChainCode:         append([]byte(nil), sum[32:]...),
+	}
+	if parent.IsPrivate() {
+		child.Key = new(zkx_types.Scalar).Add(tweak, parent.Key)
+		if child.Key.IsZero() {
+			return nil, errors.New("Invalid child index, use the next one")
+		}
+		child.Public = z.marshalPoint(z.secretBaseMult(child.Key))
+		return child, nil
+	}
+
+	// K_i = tweak*G + K
+	tx, ty := z.Curve.ScalarBaseMult(tweak.Bytes())
+	x, y := z.Curve.Add(tx, ty, point.X, point.Y)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, errors.New("Invalid child index, use the next one")
+	}
+	child.Public = z.marshalPoint(zkx_models.Point{X: x, Y: y})
+	return child, nil
+}
+
+// DerivePath derives the key at a path such as m/44'/0'/1/7, where ' or h marks a hardened index
+func (z *ZeroKnowledge) DerivePath(key zkx_models.ExtendedKey, path string) (*zkx_models.ExtendedKey, error) {
+	indices, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	current := &key
+	for _, index := range indices {
+		child, err := z.DeriveChild(*current, index)
+		if err != nil {
+			return nil, err
+		}
+		if current != &key && current.Key != nil {
+			current.Key.Destroy()
+		}
+		current = child
+	}
+	return current, nil
+}
+
+// ParsePath parses path notation into child indices, the path starts with m or M for the key it is applied to
+func ParsePath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if parts[0] != "m" && parts[0] != "M" {
+		return nil, errors.New("Path must start with m")
+	}
+	indices := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		hardened := strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h")
+		if hardened {
+			part = part[:len(part)-1]
+		}
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || uint32(index) >= HardenedOffset {
+			return nil, errors.New("Invalid path index")
+		}
+		if hardened {
+			index += uint64(HardenedOffset)
+		}
+		indices = append(indices, uint32(index))
+	}
+	return indices, nil
+}
+
+// FormatPath renders child indices in path notation
+func FormatPath(indices []uint32) string {
+	var path strings.Builder
+	path.WriteString("m")
+	for _, index := range indices {
+		path.WriteString("/")
+		if index >= HardenedOffset {
+			path.WriteString(strconv.FormatUint(uint64(index-HardenedOffset), 10) + "'")
+		} else {
+			path.WriteString(strconv.FormatUint(uint64(index), 10))
+		}
+	}
+	return path.String()
+}
+
+// Neuter returns the public extended key of a key, which derives the same non-hardened child points
+func Neuter(key zkx_models.ExtendedKey) zkx_models.ExtendedKey {
+	key.Key = nil
+	return key
+}
+
+// ExtendedSignature returns the signature of an extended key, proofs of CreateProofWithKey(key.Key, data) verify against it
+func (z *ZeroKnowledge) ExtendedSignature(key zkx_models.ExtendedKey) zkx_models.ZeroKnowledgeSignature {
+	return zkx_models.ZeroKnowledgeSignature{
+		Params:    key.Params,
+		Signature: key.Public,
+	}
+}
+
+// SignWithExtendedKey works like Sign, using the identity scalar of a private extended key
+func (z *ZeroKnowledge) SignWithExtendedKey(key zkx_models.ExtendedKey, data interface{}) (*zkx_models.ZeroKnowledgeData, error) {
+	if !key.IsPrivate() {
+		return nil, errors.New("Extended key is public")
+	}
+	payload := fmt.Sprint(data)
+	return &zkx_models.ZeroKnowledgeData{
+		Data:  payload,
+		Proof: z.CreateProofWithKey(key.Key, payload),
+	}, nil
+}
+
+// SerializeExtendedKey encodes an extended key with a checksum, private keys must be stored like secrets
+func (z *ZeroKnowledge) SerializeExtendedKey(key zkx_models.ExtendedKey) (string, error) {
+	point, err := z.unmarshalPoint(key.Public)
+	if err != nil {
+		return "", err
+	}
+	if len(key.ChainCode) != 32 || len(key.ParentFingerprint) != 4 {
+		return "", errors.New("Malformed extended key")
+	}
+	buf := make([]byte, 0, extendedKeySize+4)
+	if key.IsPrivate() {
+		buf = append(buf, extendedPrivateVersion...)
+	} else {
+		buf = append(buf, extendedPublicVersion...)
+	}
+	buf = append(buf, key.Depth)
+	buf = append(buf, key.ParentFingerprint...)
+	buf = binary.BigEndian.AppendUint32(buf, key.Index)
+	buf = append(buf, key.ChainCode...)
+	if key.IsPrivate() {
+		buf = append(append(buf, 0x00), key.Key.Bytes()...)
+	} else {
+		buf = append(buf, elliptic.MarshalCompressed(z.Curve, point.X, point.Y)...)
+	}
+	checksum := sha256.Sum256(buf)
+	buf = append(buf, checksum[:4]...)
+	encoded := base64.RawURLEncoding.EncodeToString(buf)
+	wipe(buf)
+	return encoded, nil
+}
+
+// ParseExtendedKey decodes an extended key produced by SerializeExtendedKey
+func (z *ZeroKnowledge) ParseExtendedKey(encoded string) (*zkx_models.ExtendedKey, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(buf) != extendedKeySize+4 {
+		return nil, errors.New("Malformed extended key")
+	}
+	defer wipe(buf)
+	checksum := sha256.Sum256(buf[:extendedKeySize])
+	if !hmac.Equal(checksum[:4], buf[extendedKeySize:]) {
+		return nil, errors.New("Extended key checksum mismatch")
+	}
+	key := &zkx_models.ExtendedKey{
+		Params:            z.Params,
+		Depth:             buf[4],
+		ParentFingerprint: append([]byte(nil), buf[5:9]...),
+		Index:             binary.BigEndian.Uint32(buf[9:13]),
+		ChainCode:         append([]byte(nil), buf[13:45]...),
+	}
+	material := buf[45:extendedKeySize]
+	switch {
+	case bytes.Equal(buf[:4], extendedPrivateVersion) && material[0] == 0x00:
+		if new(big.Int).SetBytes(material[1:]).Cmp(z.Curve.Params().N) >= 0 {
+			return nil, errors.New("Malformed extended key")
+		}
+		key.Key, err = zkx_types.NewScalar(z.scalarOrder(), material[1:])
+		if err != nil || key.Key.IsZero() {
+			return nil, errors.New("Malformed extended key")
+		}
+		key.Public = z.marshalPoint(z.secretBaseMult(key.Key))
+	case bytes.Equal(buf[:4], extendedPublicVersion):
+		x, y := elliptic.UnmarshalCompressed(z.Curve, material)
+		if x == nil {
+			return nil, errors.New("Invalid curve point")
+		}
+		key.Public = z.marshalPoint(zkx_models.Point{X: x, Y: y})
+	default:
+		return nil, errors.New("Unknown extended key version")
+	}
+	return key, nil
+}
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"bytes"                                   // Import package for byte slice comparison
+	"encoding/json"                           // Import package for JSON encoding and decoding
+	"testing"                                 // Import package for testing
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+func TestExtendedKeyDerivation(t *testing.T) {
+	z := newTestZK(t)
+	master := z.MasterKey(testSecret("alice"))
+	child, err := z.DerivePath(*master, "m/44'/0'/1/7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := z.SignWithExtendedKey(*child, "login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !z.Verify(*signed, z.ExtendedSignature(*child), nil) {
+		t.Fatal("proof of a derived key does not verify")
+	}
+
+	// Public parents derive the same non-hardened children
+	parent, err := z.DerivePath(*master, "m/44'/0'/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := z.DeriveChild(Neuter(*parent), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if public.IsPrivate() || !bytes.Equal(public.Public, child.Public) {
+		t.Fatal("public derivation gives another point")
+	}
+	if _, err := z.DeriveChild(Neuter(*parent), HardenedOffset); err == nil {
+		t.Fatal("public key derived a hardened child")
+	}
+}
+
+func TestExtendedKeySerialization(t *testing.T) {
+	z := newTestZK(t)
+	key, err := z.DerivePath(*z.MasterKey(testSecret("alice")), "m/1'/2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, original := range []zkx_models.ExtendedKey{*key, Neuter(*key)} {
+		encoded, err := z.SerializeExtendedKey(original)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := z.ParseExtendedKey(encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed.IsPrivate() != original.IsPrivate() || !bytes.Equal(parsed.Public, original.Public) || !bytes.Equal(parsed.ChainCode, original.ChainCode) {
+			t.Fatal("extended key changed in a round trip")
+		}
+		tampered := []byte(encoded)
+		tampered[10] ^= 1
+		if _, err := z.ParseExtendedKey(string(tampered)); err == nil {
+			t.Fatal("tampered extended key parsed")
+		}
+	}
+}
+
+// Regression: the identity scalar used to marshal to {} and come back as an unusable private key
+func TestExtendedKeyJSON(t *testing.T) {
+	z := newTestZK(t)
+	key, err := z.DerivePath(*z.MasterKey(testSecret("alice")), "m/0/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := key.ToJSON(); err == nil {
+		t.Fatal("private extended key was marshalled")
+	}
+	if _, err := json.Marshal(struct{ Key zkx_models.ExtendedKey }{*key}); err == nil {
+		t.Fatal("private extended key was marshalled inside another document")
+	}
+
+	public := Neuter(*key)
+	data, err := public.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := zkx_models.ExtendedKey{}
+	if err := decoded.FromJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.IsPrivate() || !bytes.Equal(decoded.Public, key.Public) {
+		t.Fatal("public extended key changed in a JSON round trip")
+	}
+	child, err := z.DeriveChild(decoded, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := z.DeriveChild(*key, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(child.Public, expected.Public) {
+		t.Fatal("decoded public key derives another child")
+	}
+	if err := decoded.FromJSON([]byte(`{"Key":{},"Public":"AA=="}`)); err != nil || decoded.IsPrivate() {
+		t.Fatal("JSON produced a private extended key")
+	}
+}
+
+// Regression: the depth byte must not wrap around from 255 to the depth of a master key
+func TestExtendedKeyMaximumDepth(t *testing.T) {
+	z := newTestZK(t)
+	key := z.MasterKey(testSecret("alice"))
+	for depth := 1; depth <= 255; depth++ {
+		child, err := z.DeriveChild(*key, 0)
+		if err != nil {
+			t.Fatalf("derivation to depth %d: %v", depth, err)
+		}
+		key = child
+	}
+	if key.Depth != 255 {
+		t.Fatalf("key is at depth %d", key.Depth)
+	}
+	if _, err := z.DeriveChild(*key, 0); err == nil {
+		t.Fatal("derived a child beyond the maximum depth")
+	}
+	if _, err := z.DeriveChild(Neuter(*key), 0); err == nil {
+		t.Fatal("derived a public child beyond the maximum depth")
+	}
+}
@@ -0,0 +1,21 @@
+package core
+
+import (
+	"testing"                               // Import package for testing
+	zkx_types "tmp/src/ZeroKnowledge/types" // Import Zero Knowledge types
+)
+
+// newTestZK creates the instance the tests run with
+func newTestZK(t *testing.T) *ZeroKnowledge {
+	t.Helper()
+	z, err := New("secp256k1", "sha3_256", zkx_types.NewSecretKey([]byte("test-jwt-secret")), "HS256", 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return z
+}
+
+// testSecret returns the secret of a named test identity
+func testSecret(name string) *zkx_types.SecretKey {
+	return zkx_types.NewSecretKey([]byte("secret of " + name))
+}
@@ -0,0 +1,316 @@
+package core
+
+import (
+	"bytes"                                   // Import package for byte slice comparison
+	"errors"                                  // Import package for error handling
+	"math/big"                                // Import package for big integer arithmetic
+	"sort"                                    // Import package for sorting
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+	zkx_utils "tmp/src/ZeroKnowledge/utils"   // Import Zero Knowledge utility functions
+)
+
+// MuSigAggregateSignatures aggregates the signatures of the co-signers into one group signature
+func (z *ZeroKnowledge) MuSigAggregateSignatures(signatures []zkx_models.ZeroKnowledgeSignature) (*zkx_models.MuSigKeyAggregation, error) {
+	if len(signatures) == 0 {
+		return nil, errors.New("No signatures to aggregate")
+	}
+
+	// Sort the points so that every co-signer derives the same group key
+	points := make([][]byte, len(signatures))
+	for i, signature := range signatures {
+		if _, err := z.unmarshalPoint(signature.Signature); err != nil {
+			return nil, err
+		}
+		points[i] = signature.Signature
+	}
+	sort.Slice(points, func(i, j int) bool { return bytes.Compare(points[i], points[j]) < 0 })
+	for i := 1; i < len(points); i++ {
+		if bytes.Equal(points[i-1], points[i]) {
+			return nil, errors.New("Signature appears twice in the aggregation")
+		}
+	}
+
+	// Hash the whole list so that each coefficient depends on every co-signer
+	list := z.Hash(append([]interface{}{"MuSig/KeyAggList"}, toInterfaces(points)...)...)
+
+	var x, y *big.Int
+	coefficients := make([][]byte, len(points))
+	for i, point := range points {
+		a := z.Hash("MuSig/KeyAggCoef", list, point)
+		coefficients[i] = zkx_utils.IntToBytes(a)
+
+		// Add a_i * Y_i to the group key
+		p, _ := z.unmarshalPoint(point)
+		px, py := z.Curve.ScalarMult(p.X, p.Y, a.Bytes())
+		if x == nil {
+			x, y = px, py
+		} else {
+			x, y = z.Curve.Add(x, y, px, py)
+		}
+	}
+	if !z.Curve.IsOnCurve(x, y) {
+		return nil, errors.New("Aggregated signature is the point at infinity")
+	}
+
+	return &zkx_models.MuSigKeyAggregation{
+		Signatures:   points,
+		Coefficients: coefficients,
+		Signature: zkx_models.ZeroKnowledgeSignature{
+			Params:    z.Params,
+			Signature: z.marshalPoint(zkx_models.Point{X: x, Y: y}),
+		},
+	}, nil
+}
+
+// MuSigNonceGen creates the secret nonce pair of a co-signer and the public nonce sent in the first round
+func (z *ZeroKnowledge) MuSigNonceGen(secret *zkx_types.SecretKey) (*zkx_models.MuSigSecretNonce, *zkx_models.MuSigPublicNonce, error) {
+	k1, err := z.randomSecretScalar()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer k1.Destroy()
+	k2, err := z.randomSecretScalar()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer k2.Destroy()
+	signature := z.CreateSignature(secret)
+	secretNonce := &zkx_models.MuSigSecretNonce{
+		K1: k1.Bytes(),
+		K2: k2.Bytes(),
+	}
+	publicNonce := &zkx_models.MuSigPublicNonce{
+		Signature: signature.Signature,
+		R1:        z.marshalPoint(z.secretBaseMult(k1)),
+		R2:        z.marshalPoint(z.secretBaseMult(k2)),
+	}
+	return secretNonce, publicNonce, nil
+}
+
+// MuSigStartSession sums the public nonces of every co-signer into a signing session for the data
+func (z *ZeroKnowledge) MuSigStartSession(agg *zkx_models.MuSigKeyAggregation, nonces []zkx_models.MuSigPublicNonce, data string) (*zkx_models.MuSigSession, error) {
+	if len(nonces) != len(agg.Signatures) {
+		return nil, errors.New("Expected one nonce per co-signer")
+	}
+
+	// Every co-signer contributes exactly one nonce, so a repeated nonce cannot stand in for a missing one
+	var r1x, r1y, r2x, r2y *big.Int
+	seen := make(map[string]bool, len(nonces))
+	for _, nonce := range nonces {
+		if _, err := z.muSigCoefficient(agg, nonce.Signature); err != nil {
+			return nil, err
+		}
+		if seen[string(nonce.Signature)] {
+			return nil, errors.New("Co-signer sent more than one nonce")
+		}
+		seen[string(nonce.Signature)] = true
+		r1, err := z.unmarshalPoint(nonce.R1)
+		if err != nil {
+			return nil, err
+		}
+		r2, err := z.unmarshalPoint(nonce.R2)
+		if err != nil {
+			return nil, err
+		}
+		if r1x == nil {
+			r1x, r1y, r2x, r2y = r1.X, r1.Y, r2.X, r2.Y
+			continue
+		}
+		r1x, r1y = z.Curve.Add(r1x, r1y, r1.X, r1.Y)
+		r2x, r2y = z.Curve.Add(r2x, r2y, r2.X, r2.Y)
+	}
+	if !z.Curve.IsOnCurve(r1x, r1y) || !z.Curve.IsOnCurve(r2x, r2y) {
+		return nil, errors.New("Aggregated nonce is the point at infinity")
+	}
+
+	return &zkx_models.MuSigSession{
+		KeyAggregation: *agg,
+		R1:             z.marshalPoint(zkx_models.Point{X: r1x, Y: r1y}),
+		R2:             z.marshalPoint(zkx_models.Point{X: r2x, Y: r2y}),
+		Data:           data,
+	}, nil
+}
+
+// MuSigPartialSign produces the second round response of a co-signer and burns its secret nonce
+func (z *ZeroKnowledge) MuSigPartialSign(secret *zkx_types.SecretKey, nonce *zkx_models.MuSigSecretNonce, session *zkx_models.MuSigSession) (*zkx_models.MuSigPartialSignature, error) {
+	if len(nonce.K1) == 0 || len(nonce.K2) == 0 {
+		return nil, errors.New("Secret nonce was already used")
+	}
+
+	// The session comes from the coordinator, never sign for coefficients or a group key it made up
+	if err := z.muSigCheckAggregation(&session.KeyAggregation); err != nil {
+		return nil, err
+	}
+	k1, err := zkx_types.NewScalar(z.scalarOrder(), nonce.K1)
+	if err != nil {
+		return nil, err
+	}
+	defer k1.Destroy()
+	k2, err := zkx_types.NewScalar(z.scalarOrder(), nonce.K2)
+	if err != nil {
+		return nil, err
+	}
+	defer k2.Destroy()
+
+	// Never sign twice with the same nonce, that would leak the secret
+	wipe(nonce.K1)
+	wipe(nonce.K2)
+	nonce.K1, nonce.K2 = nil, nil
+
+	signature := z.CreateSignature(secret)
+	a, err := z.muSigCoefficient(&session.KeyAggregation, signature.Signature)
+	if err != nil {
+		return nil, err
+	}
+	b, c, err := z.muSigChallenge(session)
+	if err != nil {
+		return nil, err
+	}
+
+	// m_i = k1 + b*k2 - c*a_i*x_i mod N
+	key := z.secretScalar(secret)
+	defer key.Destroy()
+	ca := z.toScalar(new(big.Int).Mul(c, a))
+	m := new(zkx_types.Scalar).Mul(z.toScalar(b), k2)
+	m.Add(m, k1)
+	m.Sub(m, new(zkx_types.Scalar).Mul(ca, key))
+
+	return &zkx_models.MuSigPartialSignature{
+		Signature: signature.Signature,
+		M:         m.Bytes(),
+	}, nil
+}
+
+// MuSigPartialVerify checks the response of a single co-signer against its public nonce
+func (z *ZeroKnowledge) MuSigPartialVerify(partial zkx_models.MuSigPartialSignature, nonce zkx_models.MuSigPublicNonce, session *zkx_models.MuSigSession) bool {
+	if !bytes.Equal(partial.Signature, nonce.Signature) {
+		return false
+	}
+	a, err := z.muSigCoefficient(&session.KeyAggregation, partial.Signature)
+	if err != nil {
+		return false
+	}
+	b, c, err := z.muSigChallenge(session)
+	if err != nil {
+		return false
+	}
+	point, err := z.unmarshalPoint(partial.Signature)
+	if err != nil {
+		return false
+	}
+	r1, err := z.unmarshalPoint(nonce.R1)
+	if err != nil {
+		return false
+	}
+	r2, err := z.unmarshalPoint(nonce.R2)
+	if err != nil {
+		return false
+	}
+
+	// m_i*G + c*a_i*Y_i must equal R1_i + b*R2_i
+	ca := new(big.Int).Mod(new(big.Int).Mul(c, a), z.Curve.Params().N)
+	mx, my := z.Curve.ScalarBaseMult(partial.M)
+	yx, yy := z.Curve.ScalarMult(point.X, point.Y, ca.Bytes())
+	lx, ly := z.Curve.Add(mx, my, yx, yy)
+	bx, by := z.Curve.ScalarMult(r2.X, r2.Y, b.Bytes())
+	rx, ry := z.Curve.Add(r1.X, r1.Y, bx, by)
+	return lx.Cmp(rx) == 0 && ly.Cmp(ry) == 0
+}
+
+// MuSigAggregatePartials combines the partial responses into a proof verifiable against the group signature
+func (z *ZeroKnowledge) MuSigAggregatePartials(session *zkx_models.MuSigSession, partials []zkx_models.MuSigPartialSignature) (*zkx_models.ZeroKnowledgeData, error) {
+	if len(partials) != len(session.KeyAggregation.Signatures) {
+		return nil, errors.New("Expected one partial signature per co-signer")
+	}
+	_, c, err := z.muSigChallenge(session)
+	if err != nil {
+		return nil, err
+	}
+
+	m := new(big.Int)
+	seen := make(map[string]bool, len(partials))
+	for _, partial := range partials {
+		if _, err := z.muSigCoefficient(&session.KeyAggregation, partial.Signature); err != nil {
+			return nil, err
+		}
+		if seen[string(partial.Signature)] {
+			return nil, errors.New("Co-signer sent more than one partial signature")
+		}
+		seen[string(partial.Signature)] = true
+		m.Add(m, new(big.Int).SetBytes(partial.M))
+	}
+	m.Mod(m, z.Curve.Params().N)
+
+	// The result has the exact shape of a single signer proof
+	return &zkx_models.ZeroKnowledgeData{
+		Data: session.Data,
+		Proof: zkx_models.ZeroKnowledgeProof{
+			Params: z.Params,
+			C:      zkx_utils.IntToBytes(c),
+			M:      zkx_utils.IntToBytes(m),
+		},
+	}, nil
+}
+
+// muSigCheckAggregation recomputes the aggregation from its signature points and compares the result
+func (z *ZeroKnowledge) muSigCheckAggregation(agg *zkx_models.MuSigKeyAggregation) error {
+	signatures := make([]zkx_models.ZeroKnowledgeSignature, len(agg.Signatures))
+	for i, point := range agg.Signatures {
+		signatures[i] = zkx_models.ZeroKnowledgeSignature{Params: z.Params, Signature: point}
+	}
+	expected, err := z.MuSigAggregateSignatures(signatures)
+	if err != nil {
+		return err
+	}
+	if len(expected.Coefficients) != len(agg.Coefficients) || !bytes.Equal(expected.Signature.Signature, agg.Signature.Signature) || agg.Signature.Service != "" {
+		return errors.New("Key aggregation does not match its co-signers")
+	}
+	for i := range expected.Coefficients {
+		if !bytes.Equal(expected.Coefficients[i], agg.Coefficients[i]) || !bytes.Equal(expected.Signatures[i], agg.Signatures[i]) {
+			return errors.New("Key aggregation does not match its co-signers")
+		}
+	}
+	return nil
+}
+
+// muSigCoefficient looks up the key aggregation coefficient of a co-signer
+func (z *ZeroKnowledge) muSigCoefficient(agg *zkx_models.MuSigKeyAggregation, signature []byte) (*big.Int, error) {
+	for i, point := range agg.Signatures {
+		if bytes.Equal(point, signature) {
+			return new(big.Int).SetBytes(agg.Coefficients[i]), nil
+		}
+	}
+	return nil, errors.New("Signature is not part of the aggregation")
+}
+
+// muSigChallenge derives the nonce coefficient b and the Schnorr challenge c of a session
+func (z *ZeroKnowledge) muSigChallenge(session *zkx_models.MuSigSession) (*big.Int, *big.Int, error) {
+	r1, err := z.unmarshalPoint(session.R1)
+	if err != nil {
+		return nil, nil, err
+	}
+	r2, err := z.unmarshalPoint(session.R2)
+	if err != nil {
+		return nil, nil, err
+	}
+	b := z.Hash("MuSig/NonceCoef", session.R1, session.R2, session.KeyAggregation.Signature.Signature, session.Data)
+
+	// R = R1 + b*R2 is the commitment seen by the verifier
+	bx, by := z.Curve.ScalarMult(r2.X, r2.Y, b.Bytes())
+	rx, ry := z.Curve.Add(r1.X, r1.Y, bx, by)
+	if !z.Curve.IsOnCurve(rx, ry) {
+		return nil, nil, errors.New("Session nonce is the point at infinity")
+	}
+	c := z.Hash(session.Data, z.marshalPoint(zkx_models.Point{X: rx, Y: ry}))
+	return b, c, nil
+}
+
+// toInterfaces widens a list of byte slices for the variadic Hash
+func toInterfaces(values [][]byte) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"testing"                                 // Import package for testing
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+)
+
+// muSigGroup runs key aggregation and the first round for the named co-signers
+func muSigGroup(t *testing.T, z *ZeroKnowledge, names ...string) (*zkx_models.MuSigKeyAggregation, []*zkx_types.SecretKey, []*zkx_models.MuSigSecretNonce, []zkx_models.MuSigPublicNonce) {
+	t.Helper()
+	var signatures []zkx_models.ZeroKnowledgeSignature
+	var secrets []*zkx_types.SecretKey
+	var secretNonces []*zkx_models.MuSigSecretNonce
+	var publicNonces []zkx_models.MuSigPublicNonce
+	for _, name := range names {
+		secret := testSecret(name)
+		secrets = append(secrets, secret)
+		signatures = append(signatures, z.CreateSignature(secret))
+		secretNonce, publicNonce, err := z.MuSigNonceGen(secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		secretNonces = append(secretNonces, secretNonce)
+		publicNonces = append(publicNonces, *publicNonce)
+	}
+	agg, err := z.MuSigAggregateSignatures(signatures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return agg, secrets, secretNonces, publicNonces
+}
+
+func TestMuSigRoundTrip(t *testing.T) {
+	z := newTestZK(t)
+	agg, secrets, secretNonces, publicNonces := muSigGroup(t, z, "alice", "bob", "carol")
+	session, err := z.MuSigStartSession(agg, publicNonces, "group login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var partials []zkx_models.MuSigPartialSignature
+	for i, secret := range secrets {
+		partial, err := z.MuSigPartialSign(secret, secretNonces[i], session)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !z.MuSigPartialVerify(*partial, publicNonces[i], session) {
+			t.Fatalf("partial signature %d does not verify", i)
+		}
+		partials = append(partials, *partial)
+	}
+	proof, err := z.MuSigAggregatePartials(session, partials)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !z.Verify(*proof, agg.Signature, nil) {
+		t.Fatal("aggregated proof does not verify against the group signature")
+	}
+
+	proof.Data = "other data"
+	if z.Verify(*proof, agg.Signature, nil) {
+		t.Fatal("aggregated proof verifies over other data")
+	}
+	tampered := partials[0]
+	tampered.M = append([]byte(nil), tampered.M...)
+	tampered.M[0] ^= 1
+	if z.MuSigPartialVerify(tampered, publicNonces[0], session) {
+		t.Fatal("tampered partial signature verifies")
+	}
+}
+
+func TestMuSigNonceReuse(t *testing.T) {
+	z := newTestZK(t)
+	agg, secrets, secretNonces, publicNonces := muSigGroup(t, z, "alice", "bob")
+	session, err := z.MuSigStartSession(agg, publicNonces, "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := z.MuSigPartialSign(secrets[0], secretNonces[0], session); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := z.MuSigPartialSign(secrets[0], secretNonces[0], session); err == nil {
+		t.Fatal("secret nonce was used twice")
+	}
+}
+
+func TestMuSigStartSessionRejectsDuplicateNonces(t *testing.T) {
+	z := newTestZK(t)
+	agg, _, _, publicNonces := muSigGroup(t, z, "alice", "bob", "carol")
+	duplicated := []zkx_models.MuSigPublicNonce{publicNonces[0], publicNonces[0], publicNonces[1]}
+	if _, err := z.MuSigStartSession(agg, duplicated, "data"); err == nil {
+		t.Fatal("session started with a duplicated nonce and a missing co-signer")
+	}
+	if _, err := z.MuSigStartSession(agg, publicNonces[:2], "data"); err == nil {
+		t.Fatal("session started without every co-signer")
+	}
+	_, _, _, strangers := muSigGroup(t, z, "mallory")
+	if _, err := z.MuSigStartSession(agg, append(publicNonces[:2:2], strangers[0]), "data"); err == nil {
+		t.Fatal("session started with the nonce of a stranger")
+	}
+}
+
+func TestMuSigAggregateRejectsDuplicateSignatures(t *testing.T) {
+	z := newTestZK(t)
+	signature := z.CreateSignature(testSecret("alice"))
+	if _, err := z.MuSigAggregateSignatures([]zkx_models.ZeroKnowledgeSignature{signature, signature}); err == nil {
+		t.Fatal("aggregation accepted the same signature twice")
+	}
+	if _, err := z.MuSigAggregateSignatures(nil); err == nil {
+		t.Fatal("aggregation accepted no signatures")
+	}
+}
+
+func TestMuSigPartialSignChecksAggregation(t *testing.T) {
+	z := newTestZK(t)
+	agg, secrets, secretNonces, publicNonces := muSigGroup(t, z, "alice", "bob")
+	session, err := z.MuSigStartSession(agg, publicNonces, "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A coordinator swapping in its own group key or coefficients gets no partial signature
+	forged := *session
+	forged.KeyAggregation.Signature = z.CreateSignature(testSecret("mallory"))
+	if _, err := z.MuSigPartialSign(secrets[0], secretNonces[0], &forged); err == nil {
+		t.Fatal("signed for a group key the co-signers do not aggregate to")
+	}
+	forged = *session
+	forged.KeyAggregation.Coefficients = [][]byte{agg.Coefficients[1], agg.Coefficients[0]}
+	if _, err := z.MuSigPartialSign(secrets[1], secretNonces[1], &forged); err == nil {
+		t.Fatal("signed with coefficients of the coordinator")
+	}
+	if _, err := z.MuSigPartialSign(secrets[0], secretNonces[0], session); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"bytes"                                   // Import package for byte slice comparison
+	"encoding/json"                           // Import package for JSON encoding and decoding
+	"errors"                                  // Import package for error handling
+	"reflect"                                 // Import package for runtime type names
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+	zkx_utils "tmp/src/ZeroKnowledge/utils"   // Import Zero Knowledge utility functions
+)
+
+// SignPayload creates a ZeroKnowledgeData object whose proof covers the type and the canonical JSON of the payload
+func SignPayload[T any](z *ZeroKnowledge, secret *zkx_types.SecretKey, payload T) (*zkx_models.ZeroKnowledgeData, error) {
+	canonical, err := zkx_utils.CanonicalJSON(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &zkx_models.ZeroKnowledgeData{
+		Data:  string(canonical),
+		Proof: z.CreateProof(secret, payloadTranscript[T](canonical)),
+	}, nil
+}
+
+// VerifyPayload verifies the proof over the payload and decodes it, the payload is only returned when valid and
+// signed as the same type, fields the type does not know are refused
+func VerifyPayload[T any](z *ZeroKnowledge, data zkx_models.ZeroKnowledgeData, signature zkx_models.ZeroKnowledgeSignature) (T, error) {
+	var payload T
+
+	// Only the canonical form was signed, anything else has been tampered with
+	canonical, err := zkx_utils.CanonicalJSON(json.RawMessage(data.Data))
+	if err != nil {
+		return payload, err
+	}
+	if !bytes.Equal(canonical, []byte(data.Data)) {
+		return payload, errors.New("Payload is not in canonical form")
+	}
+	if !z.Verify(data.Proof, signature, payloadTranscript[T](canonical)) {
+		return payload, errors.New("Invalid payload proof")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(canonical))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		return payload, err
+	}
+	return payload, nil
+}
+
+// payloadTranscript separates payload proofs from proofs over plain strings and from payloads of other types
+func payloadTranscript[T any](canonical []byte) []byte {
+	transcript, _ := json.Marshal([]interface{}{"Payload", payloadType[T](), json.RawMessage(canonical)})
+	return transcript
+}
+
+// payloadType names the Go type of a payload with its package path
+func payloadType[T any]() string {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Name() != "" {
+		return t.PkgPath() + "." + t.Name()
+	}
+	return t.String()
+}
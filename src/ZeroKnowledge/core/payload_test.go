@@ -0,0 +1,82 @@
+package core
+
+import (
+	"testing"                                 // Import package for testing
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+// testOrder is a payload signed by the tests
+type testOrder struct {
+	Item     string `json:"item"`
+	Quantity int    `json:"quantity"`
+}
+
+// testRefund has the same shape as testOrder but means something else
+type testRefund struct {
+	Item     string `json:"item"`
+	Quantity int    `json:"quantity"`
+}
+
+// testItem knows only part of the fields of testOrder
+type testItem struct {
+	Item string `json:"item"`
+}
+
+func TestPayloadRoundTrip(t *testing.T) {
+	z := newTestZK(t)
+	signature := z.CreateSignature(testSecret("alice"))
+	data, err := SignPayload(z, testSecret("alice"), testOrder{Item: "book", Quantity: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := data.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := zkx_models.ZeroKnowledgeData{}
+	if err := decoded.FromJSON(encoded); err != nil {
+		t.Fatal(err)
+	}
+	order, err := VerifyPayload[testOrder](z, decoded, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Item != "book" || order.Quantity != 2 {
+		t.Fatalf("decoded payload %+v", order)
+	}
+	if _, err := VerifyPayload[testOrder](z, decoded, z.CreateSignature(testSecret("mallory"))); err == nil {
+		t.Fatal("payload verifies against another signature")
+	}
+
+	tampered := decoded
+	tampered.Data = `{"item":"book","quantity":20}`
+	if _, err := VerifyPayload[testOrder](z, tampered, signature); err == nil {
+		t.Fatal("tampered payload verifies")
+	}
+	tampered.Data = `{"quantity":2,"item":"book"}`
+	if _, err := VerifyPayload[testOrder](z, tampered, signature); err == nil {
+		t.Fatal("payload in another form verifies")
+	}
+}
+
+// Regression: a payload signed as one type must not verify as another of the same shape
+func TestPayloadIsBoundToItsType(t *testing.T) {
+	z := newTestZK(t)
+	signature := z.CreateSignature(testSecret("alice"))
+	data, err := SignPayload(z, testSecret("alice"), testOrder{Item: "book", Quantity: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyPayload[testRefund](z, *data, signature); err == nil {
+		t.Fatal("order verifies as a refund")
+	}
+	if _, err := VerifyPayload[testItem](z, *data, signature); err == nil {
+		t.Fatal("order verifies as a type without its fields")
+	}
+	if z.Verify(*data, signature, nil) {
+		t.Fatal("payload proof verifies as a proof over the plain string")
+	}
+	if _, err := VerifyPayload[testOrder](z, *z.Sign(testSecret("alice"), data.Data), signature); err == nil {
+		t.Fatal("proof over the plain string verifies as a payload")
+	}
+}
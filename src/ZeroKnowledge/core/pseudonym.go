@@ -0,0 +1,104 @@
+package core
+
+import (
+	"bytes"                                   // Import package for byte slice comparison
+	"errors"                                  // Import package for error handling
+	"fmt"                                     // Import package for formatted I/O
+	"math/big"                                // Import package for big integer arithmetic
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+	zkx_utils "tmp/src/ZeroKnowledge/utils"   // Import Zero Knowledge utility functions
+)
+
+// CreatePseudonym creates the signature a secret presents to a single service
+func (z *ZeroKnowledge) CreatePseudonym(secret *zkx_types.SecretKey, service string) zkx_models.ZeroKnowledgeSignature {
+	key := z.secretScalar(secret)
+	defer key.Destroy()
+	return zkx_models.ZeroKnowledgeSignature{
+		Params:    z.Params,
+		Signature: z.marshalPoint(z.secretMult(z.basePoint(service), key)),
+		Service:   service,
+	}
+}
+
+// CreatePseudonymProof creates a proof verifiable against the pseudonym of the service
+func (z *ZeroKnowledge) CreatePseudonymProof(secret *zkx_types.SecretKey, service string, data interface{}) zkx_models.ZeroKnowledgeProof {
+	key := z.secretScalar(secret)
+	defer key.Destroy()
+	return z.createProof(z.basePoint(service), key, data)
+}
+
+// SignPseudonym creates a ZeroKnowledgeData object with a pseudonym proof for the provided data
+func (z *ZeroKnowledge) SignPseudonym(secret *zkx_types.SecretKey, service string, data interface{}) *zkx_models.ZeroKnowledgeData {
+	payload := fmt.Sprint(data)
+	return &zkx_models.ZeroKnowledgeData{
+		Data:  payload,
+		Proof: z.CreatePseudonymProof(secret, service, payload),
+	}
+}
+
+// LinkPseudonyms proves that two identities of the caller come from the same master secret
+func (z *ZeroKnowledge) LinkPseudonyms(secret *zkx_types.SecretKey, first zkx_models.ZeroKnowledgeSignature, second zkx_models.ZeroKnowledgeSignature) (*zkx_models.PseudonymLink, error) {
+	key := z.secretScalar(secret)
+	defer key.Destroy()
+	firstBase, secondBase := z.basePoint(first.Service), z.basePoint(second.Service)
+	if !bytes.Equal(z.marshalPoint(z.secretMult(firstBase, key)), first.Signature) ||
+		!bytes.Equal(z.marshalPoint(z.secretMult(secondBase, key)), second.Signature) {
+		return nil, errors.New("Secret does not match the identities")
+	}
+
+	// Chaum-Pedersen proof that log_B1(P1) == log_B2(P2)
+	r, err := z.randomSecretScalar()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Destroy()
+	a1, a2 := z.secretMult(firstBase, r), z.secretMult(secondBase, r)
+	c := z.linkChallenge(first, second, a1, a2)
+	m := new(zkx_types.Scalar).Sub(r, new(zkx_types.Scalar).Mul(z.toScalar(c), key))
+
+	return &zkx_models.PseudonymLink{
+		Params: z.Params,
+		First:  first,
+		Second: second,
+		C:      zkx_utils.IntToBytes(c),
+		M:      m.Bytes(),
+	}, nil
+}
+
+// VerifyPseudonymLink checks that both identities of the link share one master secret
+func (z *ZeroKnowledge) VerifyPseudonymLink(link zkx_models.PseudonymLink) bool {
+	first, err := z.unmarshalPoint(link.First.Signature)
+	if err != nil {
+		return false
+	}
+	second, err := z.unmarshalPoint(link.Second.Signature)
+	if err != nil {
+		return false
+	}
+	c := new(big.Int).SetBytes(link.C)
+	m := new(big.Int).SetBytes(link.M)
+	if m.Cmp(z.Curve.Params().N) >= 0 {
+		return false
+	}
+	a1 := z.commitment(z.basePoint(link.First.Service), first, c, m)
+	a2 := z.commitment(z.basePoint(link.Second.Service), second, c, m)
+	return c.Cmp(z.linkChallenge(link.First, link.Second, a1, a2)) == 0
+}
+
+// basePoint returns the generator of the master identity or the hashed base of a service
+func (z *ZeroKnowledge) basePoint(service string) zkx_models.Point {
+	if service == "" {
+		return zkx_models.Point{X: z.Curve.Params().Gx, Y: z.Curve.Params().Gy}
+	}
+	x, y := zkx_utils.HashToCurve(z.Curve, []byte("Pseudonym"), []byte(service))
+	return zkx_models.Point{X: x, Y: y}
+}
+
+// linkChallenge derives the Fiat-Shamir challenge of a pseudonym link
+func (z *ZeroKnowledge) linkChallenge(first zkx_models.ZeroKnowledgeSignature, second zkx_models.ZeroKnowledgeSignature, a1 zkx_models.Point, a2 zkx_models.Point) *big.Int {
+	return z.Hash("Pseudonym/Link",
+		z.marshalPoint(z.basePoint(first.Service)), first.Signature,
+		z.marshalPoint(z.basePoint(second.Service)), second.Signature,
+		z.marshalPoint(a1), z.marshalPoint(a2))
+}
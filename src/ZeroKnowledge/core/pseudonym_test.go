@@ -0,0 +1,71 @@
+package core
+
+import (
+	"bytes"   // Import package for byte slice comparison
+	"testing" // Import package for testing
+	"time"    // Import package for handling time
+)
+
+func TestPseudonymLogin(t *testing.T) {
+	z := newTestZK(t)
+	secret := testSecret("alice")
+	master := z.CreateSignature(secret)
+	shop, forum := z.CreatePseudonym(secret, "shop"), z.CreatePseudonym(secret, "forum")
+	if bytes.Equal(shop.Signature, forum.Signature) || bytes.Equal(shop.Signature, master.Signature) {
+		t.Fatal("pseudonyms of different services coincide")
+	}
+
+	// Login accepts a pseudonym like any other signature
+	token, err := z.GenerateJWT(shop, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !z.Login(*z.SignPseudonym(secret, "shop", token)) {
+		t.Fatal("pseudonym login failed")
+	}
+	if z.Login(*z.SignPseudonym(secret, "forum", token)) {
+		t.Fatal("proof for another service logged in")
+	}
+	if z.Login(*z.Sign(secret, token)) {
+		t.Fatal("master proof logged in as the pseudonym")
+	}
+	if z.Login(*z.SignPseudonym(testSecret("mallory"), "shop", token)) {
+		t.Fatal("proof of another secret logged in")
+	}
+}
+
+func TestPseudonymLink(t *testing.T) {
+	z := newTestZK(t)
+	secret := testSecret("alice")
+	shop, forum := z.CreatePseudonym(secret, "shop"), z.CreatePseudonym(secret, "forum")
+	link, err := z.LinkPseudonyms(secret, shop, forum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := link.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := *link
+	if err := decoded.FromJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	if !z.VerifyPseudonymLink(decoded) {
+		t.Fatal("link does not verify")
+	}
+	if _, err := z.LinkPseudonyms(testSecret("mallory"), shop, forum); err == nil {
+		t.Fatal("linked identities of another secret")
+	}
+
+	// The link only holds for the identities it was made for
+	decoded.Second = z.CreatePseudonym(testSecret("mallory"), "forum")
+	if z.VerifyPseudonymLink(decoded) {
+		t.Fatal("link verifies for a foreign pseudonym")
+	}
+	decoded.Second = forum
+	decoded.M = append([]byte(nil), decoded.M...)
+	decoded.M[0] ^= 1
+	if z.VerifyPseudonymLink(decoded) {
+		t.Fatal("tampered link verifies")
+	}
+}
@@ -0,0 +1,217 @@
+package core
+
+import (
+	"crypto/hmac"                                     // Import package for HMAC construction
+	"crypto/sha256"                                   // Import SHA-256 cryptographic hash function
+	"encoding/binary"                                 // Import package for big-endian integers
+	"encoding/json"                                   // Import package for JSON encoding and decoding
+	"errors"                                          // Import package for error handling
+	"math"                                            // Import package for integer limits
+	"math/bits"                                       // Import package for counting leading zeros
+	"sync"                                            // Import package for synchronization primitives
+	"time"                                            // Import package for handling time
+	zkx_algorithms "tmp/src/ZeroKnowledge/algorithms" // Import Zero Knowledge hash algorithms
+	zkx_errors "tmp/src/ZeroKnowledge/errors"         // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models"         // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"           // Import Zero Knowledge types
+	zkx_utils "tmp/src/ZeroKnowledge/utils"           // Import Zero Knowledge utility functions
+)
+
+// puzzleSeedSize is the number of random bytes in a puzzle seed
+const puzzleSeedSize = 16
+
+// puzzleSpentCapacity is the number of solved puzzles the default store remembers within a window
+const puzzleSpentCapacity = 1 << 20
+
+// MaxPuzzleDifficulty is the highest difficulty a gate issues, the nonce of a solution has 64 bits
+const MaxPuzzleDifficulty = 64
+
+// PuzzleGate issues hashcash puzzles and checks their solutions before any curve operation runs.
+// The difficulty grows by one bit each time the number of failed logins within the window doubles
+// beyond Threshold, so clients pay more while someone is guessing. Only logins that spent a solved
+// puzzle count, so raising the difficulty for everyone costs the attacker the work of the puzzles.
+type PuzzleGate struct {
+	Algorithm     string               // Hash algorithm of ZeroKnowledge/algorithms
+	Key           *zkx_types.SecretKey // HMAC key sealing the puzzles, shared by all replicas
+	MinDifficulty int                  // Difficulty without load
+	MaxDifficulty int                  // Difficulty the gate never exceeds
+	Threshold     int                  // Failed logins per window tolerated at the minimum difficulty
+	Window        time.Duration        // Window the load is measured over, also the lifetime of a puzzle
+	Spent         ReplayStore          // Seals of solved puzzles, replicas sharing Key must share it too
+	mu            sync.Mutex           // Guards failures
+	failures      []time.Time          // Times of failed logins within the window, oldest first
+}
+
+// NewPuzzleGate creates a gate with the hash algorithm, sealing key and difficulty range
+func NewPuzzleGate(algorithm string, key *zkx_types.SecretKey, minDifficulty int, maxDifficulty int, threshold int, window time.Duration) (*PuzzleGate, error) {
+	if _, ok := zkx_algorithms.HashTypes[algorithm]; !ok {
+		return nil, errors.New("Unsupported puzzle hash algorithm")
+	}
+	if minDifficulty < 0 || maxDifficulty < minDifficulty || maxDifficulty > MaxPuzzleDifficulty || threshold < 1 {
+		return nil, errors.New("Invalid puzzle difficulty")
+	}
+	return &PuzzleGate{
+		Algorithm:     algorithm,
+		Key:           key,
+		MinDifficulty: minDifficulty,
+		MaxDifficulty: maxDifficulty,
+		Threshold:     threshold,
+		Window:        window,
+		Spent:         NewMemoryReplayStore(window, puzzleSpentCapacity),
+	}, nil
+}
+
+// Difficulty returns the difficulty the next puzzle is issued with
+func (g *PuzzleGate) Difficulty() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.difficulty(time.Now())
+}
+
+// difficulty adapts to the load of the current window, callers must hold the lock
+func (g *PuzzleGate) difficulty(now time.Time) int {
+	for len(g.failures) > 0 && now.Sub(g.failures[0]) > g.Window {
+		g.failures = g.failures[1:]
+	}
+	difficulty := g.MinDifficulty + bits.Len(uint(len(g.failures)/g.Threshold))
+	if difficulty > g.MaxDifficulty {
+		return g.MaxDifficulty
+	}
+	return difficulty
+}
+
+// Issue creates a puzzle for the login challenge, send it to the client along with the challenge
+func (g *PuzzleGate) Issue(challenge string) *zkx_models.Puzzle {
+	now := time.Now()
+	g.mu.Lock()
+	difficulty := g.difficulty(now)
+	g.mu.Unlock()
+
+	puzzle := &zkx_models.Puzzle{
+		Algorithm:  g.Algorithm,
+		Challenge:  challenge,
+		Seed:       zkx_utils.GenerateSalt(puzzleSeedSize),
+		Difficulty: difficulty,
+		Expires:    now.Add(g.Window).UTC(),
+	}
+	puzzle.Seal = g.seal(puzzle)
+	return puzzle
+}
+
+// RecordFailure counts a failed login that spent a solved puzzle, LoginWithPuzzle and VerifyWithPuzzle call it
+func (g *PuzzleGate) RecordFailure() {
+	now := time.Now()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.difficulty(now) // Forget the failures that left the window
+	g.failures = append(g.failures, now)
+}
+
+// Check verifies a solution for the login data, it only hashes and never touches the curve
+func (g *PuzzleGate) Check(solution zkx_models.PuzzleSolution, data string) error {
+	puzzle := solution.Puzzle
+	if puzzle.Challenge != data || puzzle.Algorithm != g.Algorithm || !hmac.Equal(puzzle.Seal, g.seal(&puzzle)) {
+		return zkx_errors.ErrPuzzleInvalid
+	}
+	if time.Now().After(puzzle.Expires) {
+		return zkx_errors.ErrPuzzleExpired
+	}
+	if leadingZeroBits(puzzleHash(puzzle, solution.Nonce)) < puzzle.Difficulty {
+		return zkx_errors.ErrPuzzleUnsolved
+	}
+	return nil
+}
+
+// Spend checks a solution like Check and marks its puzzle as used, so each puzzle admits a single attempt
+func (g *PuzzleGate) Spend(solution zkx_models.PuzzleSolution, data string) error {
+	if err := g.Check(solution, data); err != nil {
+		return err
+	}
+
+	// A puzzle is remembered for the window, by then it has expired
+	err := g.Spent.Remember(solution.Puzzle.Seal, time.Now())
+	if errors.Is(err, zkx_errors.ErrProofReplayed) {
+		return zkx_errors.ErrPuzzleSpent
+	}
+	return err
+}
+
+// SolvePuzzle searches the nonce that solves a puzzle, it takes about 2^Difficulty hashes
+func SolvePuzzle(puzzle zkx_models.Puzzle) (*zkx_models.PuzzleSolution, error) {
+	if _, ok := zkx_algorithms.HashTypes[puzzle.Algorithm]; !ok {
+		return nil, errors.New("Unsupported puzzle hash algorithm")
+	}
+	if puzzle.Difficulty > MaxPuzzleDifficulty {
+		return nil, errors.New("Puzzle difficulty is too high")
+	}
+	for nonce := uint64(0); ; nonce++ {
+		if leadingZeroBits(puzzleHash(puzzle, nonce)) >= puzzle.Difficulty {
+			return &zkx_models.PuzzleSolution{Puzzle: puzzle, Nonce: nonce}, nil
+		}
+		if nonce == math.MaxUint64 {
+			return nil, errors.New("Puzzle has no solution")
+		}
+	}
+}
+
+// LoginWithPuzzle spends the puzzle solution first and only then performs the login
+func (z *ZeroKnowledge) LoginWithPuzzle(gate *PuzzleGate, solution zkx_models.PuzzleSolution, loginData zkx_models.ZeroKnowledgeData) error {
+	if err := gate.Spend(solution, loginData.Data); err != nil {
+		return err
+	}
+	if !z.Login(loginData) {
+		gate.RecordFailure()
+		return zkx_errors.ErrInvalidProof
+	}
+	return nil
+}
+
+// IssueWithPuzzle issues a challenge like Issue together with a puzzle bound to it
+func (s *ChallengeService) IssueWithPuzzle(user string, session string, gate *PuzzleGate) (*zkx_models.Challenge, *zkx_models.Puzzle, error) {
+	challenge, err := s.Issue(user, session)
+	if err != nil {
+		return nil, nil, err
+	}
+	return challenge, gate.Issue(challenge.Data()), nil
+}
+
+// VerifyWithPuzzle spends the puzzle solution first and only then verifies the proof like Verify
+func (s *ChallengeService) VerifyWithPuzzle(user string, session string, gate *PuzzleGate, solution zkx_models.PuzzleSolution, loginData zkx_models.ZeroKnowledgeData, signature zkx_models.ZeroKnowledgeSignature) error {
+	if err := gate.Spend(solution, loginData.Data); err != nil {
+		return err
+	}
+	if err := s.Verify(user, session, loginData, signature); err != nil {
+		gate.RecordFailure()
+		return err
+	}
+	return nil
+}
+
+// seal computes the HMAC of the server over a puzzle
+func (g *PuzzleGate) seal(puzzle *zkx_models.Puzzle) []byte {
+	content, _ := json.Marshal([]interface{}{"Puzzle", puzzle.Algorithm, puzzle.Challenge, puzzle.Seed, puzzle.Difficulty, puzzle.Expires.UnixNano()})
+	mac := hmac.New(sha256.New, g.Key.Bytes())
+	mac.Write(content)
+	return mac.Sum(nil)
+}
+
+// puzzleHash hashes the seed, the challenge and the nonce with the algorithm of the puzzle
+func puzzleHash(puzzle zkx_models.Puzzle, nonce uint64) []byte {
+	hash := zkx_algorithms.HashTypes[puzzle.Algorithm]()
+	hash.Write(puzzle.Seed)
+	hash.Write([]byte(puzzle.Challenge))
+	hash.Write(binary.BigEndian.AppendUint64(nil, nonce))
+	return hash.Sum(nil)
+}
+
+// leadingZeroBits counts the zero bits at the start of a digest
+func leadingZeroBits(digest []byte) int {
+	count := 0
+	for _, b := range digest {
+		if b != 0 {
+			return count + bits.LeadingZeros8(b)
+		}
+		count += 8
+	}
+	return count
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"errors"                                  // Import package for error handling
+	"testing"                                 // Import package for testing
+	"time"                                    // Import package for handling time
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+)
+
+// newTestGate creates a gate with a difficulty that solves quickly
+func newTestGate(t *testing.T) *PuzzleGate {
+	t.Helper()
+	gate, err := NewPuzzleGate("sha256", testSecret("gate"), 4, 8, 2, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return gate
+}
+
+func TestPuzzleRoundTrip(t *testing.T) {
+	z := newTestZK(t)
+	service := NewChallengeService(z, NewMemoryChallengeStore(), time.Minute)
+	gate := newTestGate(t)
+	secret := testSecret("alice")
+	challenge, puzzle, err := service.IssueWithPuzzle("alice", "session", gate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	solution, err := SolvePuzzle(*puzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loginData := z.Sign(secret, challenge.Data())
+	if err := service.VerifyWithPuzzle("alice", "session", gate, *solution, *loginData, z.CreateSignature(secret)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPuzzleRejectsBadSolutions(t *testing.T) {
+	gate := newTestGate(t)
+	puzzle := gate.Issue("challenge")
+	solution, err := SolvePuzzle(*puzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gate.Check(*solution, "other challenge"); !errors.Is(err, zkx_errors.ErrPuzzleInvalid) {
+		t.Fatalf("solution for another challenge gave %v", err)
+	}
+	easier := *solution
+	easier.Puzzle.Difficulty = 0
+	if err := gate.Check(easier, "challenge"); !errors.Is(err, zkx_errors.ErrPuzzleInvalid) {
+		t.Fatalf("puzzle with a lowered difficulty gave %v", err)
+	}
+	for nonce := solution.Nonce + 1; ; nonce++ {
+		wrong := *solution
+		wrong.Nonce = nonce
+		if leadingZeroBits(puzzleHash(wrong.Puzzle, nonce)) < puzzle.Difficulty {
+			if err := gate.Check(wrong, "challenge"); !errors.Is(err, zkx_errors.ErrPuzzleUnsolved) {
+				t.Fatalf("wrong nonce gave %v", err)
+			}
+			break
+		}
+	}
+
+	expired, err := NewPuzzleGate("sha256", testSecret("gate"), 0, 0, 1, -time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale, _ := SolvePuzzle(*expired.Issue("challenge"))
+	if err := expired.Check(*stale, "challenge"); !errors.Is(err, zkx_errors.ErrPuzzleExpired) {
+		t.Fatalf("expired puzzle gave %v", err)
+	}
+}
+
+// Regression: a solved puzzle used to admit any number of login attempts
+func TestPuzzleIsSpentOnce(t *testing.T) {
+	z := newTestZK(t)
+	gate := newTestGate(t)
+	puzzle := gate.Issue("challenge")
+	solution, err := SolvePuzzle(*puzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loginData := z.Sign(testSecret("alice"), "challenge")
+	if err := z.LoginWithPuzzle(gate, *solution, *loginData); !errors.Is(err, zkx_errors.ErrInvalidProof) {
+		t.Fatalf("login without a token gave %v", err)
+	}
+	if err := z.LoginWithPuzzle(gate, *solution, *loginData); !errors.Is(err, zkx_errors.ErrPuzzleSpent) {
+		t.Fatalf("second attempt with one puzzle gave %v", err)
+	}
+	if err := gate.Spend(*solution, "challenge"); !errors.Is(err, zkx_errors.ErrPuzzleSpent) {
+		t.Fatalf("spent puzzle gave %v", err)
+	}
+}
+
+func TestPuzzleDifficultyAdapts(t *testing.T) {
+	gate := newTestGate(t)
+	if gate.Difficulty() != 4 {
+		t.Fatalf("idle difficulty is %d", gate.Difficulty())
+	}
+	for i := 0; i < 64; i++ {
+		gate.RecordFailure()
+	}
+	if gate.Difficulty() != 8 {
+		t.Fatalf("difficulty under load is %d", gate.Difficulty())
+	}
+	if _, err := NewPuzzleGate("unknown", testSecret("gate"), 1, 2, 1, time.Minute); err == nil {
+		t.Fatal("gate accepted an unknown hash")
+	}
+	if _, err := NewPuzzleGate("sha256", testSecret("gate"), 1, MaxPuzzleDifficulty+1, 1, time.Minute); err == nil {
+		t.Fatal("gate accepted a difficulty no nonce can reach")
+	}
+}
+
+// Regression: requesting puzzles must not raise the difficulty for everyone, only failed logins do
+func TestPuzzleDifficultyCountsFailures(t *testing.T) {
+	z := newTestZK(t)
+	gate := newTestGate(t)
+	for i := 0; i < 64; i++ {
+		gate.Issue("challenge")
+	}
+	if gate.Difficulty() != 4 {
+		t.Fatalf("difficulty after issuing puzzles is %d", gate.Difficulty())
+	}
+	for i := 0; i < 2; i++ {
+		solution, err := SolvePuzzle(*gate.Issue("challenge"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := z.LoginWithPuzzle(gate, *solution, *z.Sign(testSecret("mallory"), "challenge")); !errors.Is(err, zkx_errors.ErrInvalidProof) {
+			t.Fatalf("failed login gave %v", err)
+		}
+	}
+	if gate.Difficulty() != 5 {
+		t.Fatalf("difficulty after failed logins is %d", gate.Difficulty())
+	}
+}
+
+func TestSolvePuzzleRefusesUnreachableDifficulty(t *testing.T) {
+	puzzle := newTestGate(t).Issue("challenge")
+	puzzle.Difficulty = MaxPuzzleDifficulty + 1
+	if _, err := SolvePuzzle(*puzzle); err == nil {
+		t.Fatal("solved a puzzle no nonce can reach")
+	}
+}
@@ -0,0 +1,166 @@
+package core
+
+import (
+	"bytes"                                   // Import package for byte slice comparison
+	"crypto/rand"                             // Import cryptographic random number generator
+	"errors"                                  // Import package for error handling
+	"sync"                                    // Import package for synchronization primitives
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+// SignatureRegistry stores the signature registered for each user
+type SignatureRegistry interface {
+	Get(user string) (zkx_models.ZeroKnowledgeSignature, bool)
+	Register(user string, signature zkx_models.ZeroKnowledgeSignature) error
+	RotationNonce(user string) ([]byte, error)
+	Swap(event zkx_models.RotationEvent, nonce []byte) error
+	Events(user string) []zkx_models.RotationEvent
+}
+
+// DeviceRegistry stores the device keys of each account
+type DeviceRegistry interface {
+	Devices(user string) []zkx_models.DeviceKey
+	AddDevice(user string, device zkx_models.DeviceKey) error
+	RevokeDevice(user string, deviceID string) error
+}
+
+// MemoryRegistry keeps registered signatures, rotation events and device keys in memory
+type MemoryRegistry struct {
+	mu         sync.Mutex                                   // Guards the maps below
+	signatures map[string]zkx_models.ZeroKnowledgeSignature // Registered signature of each user
+	events     map[string][]zkx_models.RotationEvent        // Rotation history of each user
+	nonces     map[string][]byte                            // Pending rotation nonce of each user
+	devices    map[string][]zkx_models.DeviceKey            // Device keys of each user
+}
+
+// NewMemoryRegistry creates a new, empty MemoryRegistry
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{
+		signatures: make(map[string]zkx_models.ZeroKnowledgeSignature),
+		events:     make(map[string][]zkx_models.RotationEvent),
+		nonces:     make(map[string][]byte),
+		devices:    make(map[string][]zkx_models.DeviceKey),
+	}
+}
+
+// Get returns the signature registered for the user
+func (r *MemoryRegistry) Get(user string) (zkx_models.ZeroKnowledgeSignature, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	signature, ok := r.signatures[user]
+	return signature, ok
+}
+
+// Register stores the first signature of a user
+func (r *MemoryRegistry) Register(user string, signature zkx_models.ZeroKnowledgeSignature) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.signatures[user]; ok {
+		return errors.New("User is already registered")
+	}
+	if signature.Service == BlindIssuerService {
+		return errors.New("Blind issuance keys cannot be registered for login")
+	}
+	r.signatures[user] = signature
+	return nil
+}
+
+// RotationNonce issues the single-use nonce the next rotation proof of the user must cover
+func (r *MemoryRegistry) RotationNonce(user string) ([]byte, error) {
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.signatures[user]; !ok {
+		return nil, errors.New("Unknown user")
+	}
+	r.nonces[user] = nonce
+	return append([]byte(nil), nonce...), nil
+}
+
+// Swap replaces the registered signature only if it still equals the old one and the nonce is the
+// pending one of the user, consumes the nonce and records the event
+func (r *MemoryRegistry) Swap(event zkx_models.RotationEvent, nonce []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pending, ok := r.nonces[event.User]
+	if !ok || len(nonce) == 0 || !bytes.Equal(pending, nonce) {
+		return errors.New("Rotation nonce is unknown or was already used")
+	}
+	current, ok := r.signatures[event.User]
+	if !ok || !sameSignature(current, event.Old) {
+		return errors.New("Registered signature has changed")
+	}
+	delete(r.nonces, event.User)
+	r.signatures[event.User] = event.New
+	r.events[event.User] = append(r.events[event.User], event)
+	return nil
+}
+
+// Events returns the rotation history of the user
+func (r *MemoryRegistry) Events(user string) []zkx_models.RotationEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]zkx_models.RotationEvent(nil), r.events[user]...)
+}
+
+// Devices returns the device keys of the user, revoked ones included
+func (r *MemoryRegistry) Devices(user string) []zkx_models.DeviceKey {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]zkx_models.DeviceKey(nil), r.devices[user]...)
+}
+
+// AddDevice stores a new device key for the user
+func (r *MemoryRegistry) AddDevice(user string, device zkx_models.DeviceKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.devices[user] {
+		if existing.ID == device.ID {
+			return errors.New("Device is already enrolled")
+		}
+	}
+	r.devices[user] = append(r.devices[user], device)
+	return nil
+}
+
+// RevokeDevice marks a single device of the user as revoked
+func (r *MemoryRegistry) RevokeDevice(user string, deviceID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, device := range r.devices[user] {
+		if device.ID == deviceID {
+			r.devices[user][i].Revoked = true
+			return nil
+		}
+	}
+	return errors.New("Unknown device")
+}
+
+// LoginUser performs a login and checks that the token was issued for the signature registered to the user,
+// so tokens of a rotated signature stop working
+func (z *ZeroKnowledge) LoginUser(registry SignatureRegistry, user string, loginData zkx_models.ZeroKnowledgeData) bool {
+	registered, ok := registry.Get(user)
+	if !ok {
+		return false
+	}
+	claims, err := z.verifyJWT([]byte(loginData.Data))
+	if err != nil {
+		return false
+	}
+	if subject, ok := claims["sub"].(string); ok && subject != user {
+		return false
+	}
+	signature, err := jwtSignature(claims)
+	if err != nil || !sameSignature(signature, registered) {
+		return false
+	}
+	return z.Verify(loginData, registered, nil)
+}
+
+// sameSignature reports whether two signatures are the same identity
+func sameSignature(a zkx_models.ZeroKnowledgeSignature, b zkx_models.ZeroKnowledgeSignature) bool {
+	return a.Params.Algorithm == b.Params.Algorithm && a.Service == b.Service && bytes.Equal(a.Signature, b.Signature)
+}
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"math/big"                                // Import package for big integer arithmetic
+	"sync"                                    // Import package for synchronization primitives
+	"time"                                    // Import package for handling time
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+// ReplayStore remembers the fingerprints of accepted proofs for a time window and refuses to see one twice
+type ReplayStore interface {
+	Remember(fingerprint []byte, now time.Time) error
+}
+
+// seenProof is a fingerprint remembered by a MemoryReplayStore
+type seenProof struct {
+	fingerprint string    // Fingerprint of the proof
+	expires     time.Time // Time after which the fingerprint is forgotten
+}
+
+// MemoryReplayStore keeps at most a fixed number of fingerprints in memory, each for a fixed window
+type MemoryReplayStore struct {
+	mu       sync.Mutex           // Guards the fields below
+	window   time.Duration        // How long a fingerprint is remembered
+	capacity int                  // Maximum number of remembered fingerprints
+	seen     map[string]time.Time // Expiry of every remembered fingerprint
+	order    []seenProof          // Remembered fingerprints, oldest first
+}
+
+// NewMemoryReplayStore creates a store that remembers up to capacity proofs for the given window
+func NewMemoryReplayStore(window time.Duration, capacity int) *MemoryReplayStore {
+	return &MemoryReplayStore{
+		window:   window,
+		capacity: capacity,
+		seen:     make(map[string]time.Time),
+	}
+}
+
+// Remember records a fingerprint, it fails for fingerprints seen within the window. A full store fails
+// closed rather than forgetting proofs early, since that would reopen the window for their replay.
+func (s *MemoryReplayStore) Remember(fingerprint []byte, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Forget the fingerprints whose window has passed, they expire in insertion order
+	for len(s.order) > 0 && now.After(s.order[0].expires) {
+		delete(s.seen, s.order[0].fingerprint)
+		s.order = s.order[1:]
+	}
+
+	key := string(fingerprint)
+	if _, ok := s.seen[key]; ok {
+		return zkx_errors.ErrProofReplayed
+	}
+	if len(s.order) >= s.capacity {
+		return zkx_errors.ErrReplayStoreFull
+	}
+	expires := now.Add(s.window)
+	s.seen[key] = expires
+	s.order = append(s.order, seenProof{fingerprint: key, expires: expires})
+	return nil
+}
+
+// ProofFingerprint identifies a proof by the values of its challenge and response, or a hash-based proof by its leaf and randomness
+func ProofFingerprint(proof zkx_models.ZeroKnowledgeProof) []byte {
+	if proof.Hash != nil {
+		hash := hashValues("ProofFingerprint/Hash", int(proof.Hash.Index), proof.Hash.Random)
+		return hash[:]
+	}
+	c, m := canonicalInt(proof.C), canonicalInt(proof.M)
+	hash := hashValues("ProofFingerprint", len(c), c, m)
+	return hash[:]
+}
+
+// canonicalInt encodes the integer Verify reads from the bytes, leading zeros do not change it
+func canonicalInt(b []byte) []byte {
+	return new(big.Int).SetBytes(b).Bytes()
+}
+
+// VerifyOnce verifies a proof and refuses it if the replay store has already seen it
+func (z *ZeroKnowledge) VerifyOnce(challenge interface{}, signature zkx_models.ZeroKnowledgeSignature, data interface{}, replays ReplayStore) error {
+	proof, _, ok := challengeProof(challenge, data)
+	if !ok || !z.Verify(challenge, signature, data) {
+		return zkx_errors.ErrInvalidProof
+	}
+	return replays.Remember(ProofFingerprint(proof), time.Now())
+}
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"errors"                                  // Import package for error handling
+	"math/big"                                // Import package for big integer arithmetic
+	"testing"                                 // Import package for testing
+	"time"                                    // Import package for handling time
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+)
+
+func TestVerifyOnceRejectsReplay(t *testing.T) {
+	z := newTestZK(t)
+	secret := testSecret("alice")
+	signature := z.CreateSignature(secret)
+	replays := NewMemoryReplayStore(time.Minute, 16)
+	signed := z.Sign(secret, "login")
+	if err := z.VerifyOnce(*signed, signature, nil, replays); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.VerifyOnce(*signed, signature, nil, replays); !errors.Is(err, zkx_errors.ErrProofReplayed) {
+		t.Fatalf("replayed proof gave %v", err)
+	}
+	if err := z.VerifyOnce(*z.Sign(secret, "login"), signature, nil, replays); err != nil {
+		t.Fatalf("fresh proof over the same data gave %v", err)
+	}
+	forged := *signed
+	forged.Data = "other"
+	if err := z.VerifyOnce(forged, signature, nil, replays); !errors.Is(err, zkx_errors.ErrInvalidProof) {
+		t.Fatalf("tampered proof gave %v", err)
+	}
+}
+
+// Regression: padding C or M with leading zeros used to give a replayed proof a new fingerprint
+func TestVerifyOnceRejectsPaddedReplay(t *testing.T) {
+	z := newTestZK(t)
+	secret := testSecret("alice")
+	signature := z.CreateSignature(secret)
+	replays := NewMemoryReplayStore(time.Minute, 16)
+	signed := z.Sign(secret, "login")
+	if err := z.VerifyOnce(*signed, signature, nil, replays); err != nil {
+		t.Fatal(err)
+	}
+	padded := *signed
+	padded.Proof.M = append([]byte{0}, signed.Proof.M...)
+	if err := z.VerifyOnce(padded, signature, nil, replays); !errors.Is(err, zkx_errors.ErrProofReplayed) {
+		t.Fatalf("proof with a padded response gave %v", err)
+	}
+	padded = *signed
+	padded.Proof.C = append([]byte{0, 0}, signed.Proof.C...)
+	if err := z.VerifyOnce(padded, signature, nil, replays); !errors.Is(err, zkx_errors.ErrProofReplayed) {
+		t.Fatalf("proof with a padded challenge gave %v", err)
+	}
+
+	// m + N is the same response modulo N, Verify must refuse it outright
+	shifted := *signed
+	m := new(big.Int).SetBytes(signed.Proof.M)
+	shifted.Proof.M = m.Add(m, z.Curve.Params().N).Bytes()
+	if z.Verify(shifted, signature, nil) {
+		t.Fatal("response above the curve order verifies")
+	}
+}
+
+func TestMemoryReplayStoreWindow(t *testing.T) {
+	replays := NewMemoryReplayStore(time.Minute, 1)
+	now := time.Now()
+	if err := replays.Remember([]byte("a"), now); err != nil {
+		t.Fatal(err)
+	}
+	if err := replays.Remember([]byte("b"), now); !errors.Is(err, zkx_errors.ErrReplayStoreFull) {
+		t.Fatalf("full store gave %v", err)
+	}
+	if err := replays.Remember([]byte("a"), now.Add(2*time.Minute)); err != nil {
+		t.Fatalf("expired fingerprint gave %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"bytes"                                   // Import package for byte slice comparison
+	"encoding/json"                           // Import package for JSON encoding and decoding
+	"errors"                                  // Import package for error handling
+	"fmt"                                     // Import package for formatted I/O
+	"math/big"                                // Import package for big integer arithmetic
+	"sync"                                    // Import package for synchronization primitives
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+	zkx_utils "tmp/src/ZeroKnowledge/utils"   // Import Zero Knowledge utility functions
+)
+
+// Ring signatures follow LSAG. Member i has the public point P_i = x_i*G and the point H_i hashed from
+// P_i and the scope. The signer s publishes the key image I = x_s*H_s, and every member contributes
+// L_i = s_i*G + c_i*P_i and R_i = s_i*H_i + c_i*I to a challenge chain c_{i+1} = Hash(..., L_i, R_i)
+// that only closes if the signer knows the secret behind one of the points. With a single key per
+// member this is exactly CLSAG, whose aggregation only matters for additional commitment keys.
+
+// KeyImageStore remembers the key images that were used, so each member signs at most once per scope
+type KeyImageStore interface {
+	Spend(image zkx_models.KeyImage) error
+}
+
+// MemoryKeyImageStore keeps used key images in memory
+type MemoryKeyImageStore struct {
+	mu   sync.Mutex          // Guards the map below
+	used map[string]struct{} // Used key images by scope and image
+}
+
+// NewMemoryKeyImageStore creates a new, empty MemoryKeyImageStore
+func NewMemoryKeyImageStore() *MemoryKeyImageStore {
+	return &MemoryKeyImageStore{used: make(map[string]struct{})}
+}
+
+// Spend marks a key image as used, it fails if the image was used before
+func (s *MemoryKeyImageStore) Spend(image zkx_models.KeyImage) error {
+	key := string(keyImageKey(image))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.used[key]; ok {
+		return zkx_errors.ErrKeyImageUsed
+	}
+	s.used[key] = struct{}{}
+	return nil
+}
+
+// NewRing creates a ring of the given members for one scope
+func (z *ZeroKnowledge) NewRing(scope string, members []zkx_models.ZeroKnowledgeSignature) *zkx_models.Ring {
+	return &zkx_models.Ring{
+		Params:  z.Params,
+		Scope:   scope,
+		Members: members,
+	}
+}
+
+// RingSign signs the data anonymously on behalf of the ring, the secret must belong to one of its members
+func (z *ZeroKnowledge) RingSign(secret *zkx_types.SecretKey, ring zkx_models.Ring, data interface{}) (*zkx_models.RingSignature, error) {
+	n := len(ring.Members)
+	signature := z.CreateSignature(secret)
+	signer := -1
+	for i, member := range ring.Members {
+		if bytes.Equal(member.Signature, signature.Signature) {
+			signer = i
+			break
+		}
+	}
+	if signer < 0 {
+		return nil, errors.New("Signer is not a member of the ring")
+	}
+	publics, err := z.ringPoints(ring)
+	if err != nil {
+		return nil, err
+	}
+
+	key := z.secretScalar(secret)
+	defer key.Destroy()
+	base := z.ringBase(ring.Scope, ring.Members[signer])
+	image := z.secretMult(base, key)
+	payload := fmt.Sprint(data)
+	digest := z.ringDigest(ring, payload, image)
+
+	// Start the chain at the signer with a fresh nonce
+	alpha, err := z.randomSecretScalar()
+	if err != nil {
+		return nil, err
+	}
+	defer alpha.Destroy()
+	challenges := make([]*big.Int, n)
+	responses := make([][]byte, n)
+	challenges[(signer+1)%n] = z.ringChallenge(digest, z.secretBaseMult(alpha), z.secretMult(base, alpha))
+
+	// Every other member gets a random response
+	for j := 1; j < n; j++ {
+		i := (signer + j) % n
+		s, err := z.randomSecretScalar()
+		if err != nil {
+			return nil, err
+		}
+		m := new(big.Int).SetBytes(s.Bytes())
+		L := z.commitment(z.basePoint(""), publics[i], challenges[i], m)
+		R := z.commitment(z.ringBase(ring.Scope, ring.Members[i]), image, challenges[i], m)
+		challenges[(i+1)%n] = z.ringChallenge(digest, L, R)
+		responses[i] = s.Bytes()
+	}
+
+	// Close the chain: s = alpha - c*x
+	m := new(zkx_types.Scalar).Sub(alpha, new(zkx_types.Scalar).Mul(z.toScalar(challenges[signer]), key))
+	responses[signer] = m.Bytes()
+
+	return &zkx_models.RingSignature{
+		Params:   z.Params,
+		Data:     payload,
+		KeyImage: zkx_models.KeyImage{Scope: ring.Scope, Image: z.marshalPoint(image)},
+		C:        zkx_utils.IntToBytes(challenges[0]),
+		S:        responses,
+	}, nil
+}
+
+// VerifyRingSignature checks that some member of the ring signed the data
+func (z *ZeroKnowledge) VerifyRingSignature(ring zkx_models.Ring, signature zkx_models.RingSignature) bool {
+	n := len(ring.Members)
+	if n == 0 || len(signature.S) != n || signature.KeyImage.Scope != ring.Scope {
+		return false
+	}
+	publics, err := z.ringPoints(ring)
+	if err != nil {
+		return false
+	}
+	image, err := z.unmarshalPoint(signature.KeyImage.Image)
+	if err != nil {
+		return false
+	}
+	digest := z.ringDigest(ring, signature.Data, image)
+
+	// Walk the whole chain, it must end where it started
+	c0 := new(big.Int).SetBytes(signature.C)
+	c := c0
+	for i := 0; i < n; i++ {
+		m := new(big.Int).SetBytes(signature.S[i])
+		L := z.commitment(z.basePoint(""), publics[i], c, m)
+		R := z.commitment(z.ringBase(ring.Scope, ring.Members[i]), image, c, m)
+		c = z.ringChallenge(digest, L, R)
+	}
+	return c.Cmp(c0) == 0
+}
+
+// VerifyRingSignatureOnce verifies a ring signature and spends its key image, so a second signature
+// of the same member within the scope is refused
+func (z *ZeroKnowledge) VerifyRingSignatureOnce(ring zkx_models.Ring, signature zkx_models.RingSignature, data interface{}, images KeyImageStore) error {
+	if signature.Data != fmt.Sprint(data) || !z.VerifyRingSignature(ring, signature) {
+		return zkx_errors.ErrInvalidProof
+	}
+	return images.Spend(signature.KeyImage)
+}
+
+// LinkRingSignatures reports whether two valid ring signatures of one scope come from the same member
+func LinkRingSignatures(first zkx_models.RingSignature, second zkx_models.RingSignature) bool {
+	return bytes.Equal(keyImageKey(first.KeyImage), keyImageKey(second.KeyImage))
+}
+
+// ringPoints decodes the public points of the members, pseudonyms are not allowed in a ring
+func (z *ZeroKnowledge) ringPoints(ring zkx_models.Ring) ([]zkx_models.Point, error) {
+	points := make([]zkx_models.Point, len(ring.Members))
+	for i, member := range ring.Members {
+		if member.Service != "" {
+			return nil, errors.New("Ring members must be master signatures")
+		}
+		point, err := z.unmarshalPoint(member.Signature)
+		if err != nil {
+			return nil, err
+		}
+		points[i] = point
+	}
+	return points, nil
+}
+
+// ringBase hashes a member to the point its key image is computed on
+func (z *ZeroKnowledge) ringBase(scope string, member zkx_models.ZeroKnowledgeSignature) zkx_models.Point {
+	x, y := zkx_utils.HashToCurve(z.Curve, []byte("Ring/KeyImage"), []byte(scope), member.Signature)
+	return zkx_models.Point{X: x, Y: y}
+}
+
+// ringDigest binds the challenges to the ring, the data and the key image
+func (z *ZeroKnowledge) ringDigest(ring zkx_models.Ring, data string, image zkx_models.Point) []byte {
+	members := make([][]byte, len(ring.Members))
+	for i, member := range ring.Members {
+		members[i] = member.Signature
+	}
+	digest, _ := json.Marshal([]interface{}{"Ring/Sign", ring.Scope, members, z.marshalPoint(image), data})
+	return digest
+}
+
+// ringChallenge derives the next challenge of the chain
+func (z *ZeroKnowledge) ringChallenge(digest []byte, L zkx_models.Point, R zkx_models.Point) *big.Int {
+	return z.Hash(digest, z.marshalPoint(L), z.marshalPoint(R))
+}
+
+// keyImageKey encodes a key image for comparison and storage
+func keyImageKey(image zkx_models.KeyImage) []byte {
+	key, _ := json.Marshal([]interface{}{image.Scope, image.Image})
+	return key
+}
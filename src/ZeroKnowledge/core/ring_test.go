@@ -0,0 +1,101 @@
+package core
+
+import (
+	"errors"                                  // Import package for error handling
+	"testing"                                 // Import package for testing
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+// testRing creates a ring of the named members for the scope
+func testRing(z *ZeroKnowledge, scope string, names ...string) zkx_models.Ring {
+	var members []zkx_models.ZeroKnowledgeSignature
+	for _, name := range names {
+		members = append(members, z.CreateSignature(testSecret(name)))
+	}
+	return *z.NewRing(scope, members)
+}
+
+func TestRingSignatureRoundTrip(t *testing.T) {
+	z := newTestZK(t)
+	ring := testRing(z, "vote", "alice", "bob", "carol", "dave")
+	images := NewMemoryKeyImageStore()
+	first, err := z.RingSign(testSecret("carol"), ring, "ballot 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := z.VerifyRingSignatureOnce(ring, *first, "ballot 1", images); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second signature of the same member links to the first and is refused
+	second, err := z.RingSign(testSecret("carol"), ring, "ballot 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !LinkRingSignatures(*first, *second) {
+		t.Fatal("signatures of one member do not link")
+	}
+	if err := z.VerifyRingSignatureOnce(ring, *second, "ballot 2", images); !errors.Is(err, zkx_errors.ErrKeyImageUsed) {
+		t.Fatalf("second signature of a member gave %v", err)
+	}
+	other, err := z.RingSign(testSecret("alice"), ring, "ballot 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if LinkRingSignatures(*first, *other) {
+		t.Fatal("signatures of different members link")
+	}
+	if err := z.VerifyRingSignatureOnce(ring, *other, "ballot 2", images); err != nil {
+		t.Fatal(err)
+	}
+
+	// Key images are scoped, the same member signs again in another scope
+	poll := testRing(z, "poll", "alice", "bob", "carol", "dave")
+	scoped, err := z.RingSign(testSecret("carol"), poll, "answer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if LinkRingSignatures(*first, *scoped) {
+		t.Fatal("signatures of different scopes link")
+	}
+}
+
+func TestRingSignatureRejectsTampering(t *testing.T) {
+	z := newTestZK(t)
+	ring := testRing(z, "vote", "alice", "bob", "carol")
+	signature, err := z.RingSign(testSecret("bob"), ring, "ballot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !z.VerifyRingSignature(ring, *signature) {
+		t.Fatal("ring signature does not verify")
+	}
+
+	forged := *signature
+	forged.Data = "other ballot"
+	if z.VerifyRingSignature(ring, forged) {
+		t.Fatal("ring signature verifies over other data")
+	}
+	forged = *signature
+	forged.S = append([][]byte(nil), signature.S...)
+	forged.S[2] = append([]byte(nil), signature.S[2]...)
+	forged.S[2][0] ^= 1
+	if z.VerifyRingSignature(ring, forged) {
+		t.Fatal("ring signature verifies with a tampered response")
+	}
+	forged = *signature
+	forged.KeyImage.Image = z.marshalPoint(z.secretBaseMult(z.secretScalar(testSecret("mallory"))))
+	if z.VerifyRingSignature(ring, forged) {
+		t.Fatal("ring signature verifies with another key image")
+	}
+	if z.VerifyRingSignature(testRing(z, "vote", "alice", "bob", "dave"), *signature) {
+		t.Fatal("ring signature verifies against another ring")
+	}
+	if z.VerifyRingSignature(testRing(z, "poll", "alice", "bob", "carol"), *signature) {
+		t.Fatal("ring signature verifies in another scope")
+	}
+	if _, err := z.RingSign(testSecret("mallory"), ring, "ballot"); err == nil {
+		t.Fatal("outsider signed for the ring")
+	}
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"bytes"                                   // Import package for byte slice comparison
+	"encoding/json"                           // Import package for JSON encoding and decoding
+	"errors"                                  // Import package for error handling
+	"math/big"                                // Import package for big integer arithmetic
+	"time"                                    // Import package for handling time
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+	zkx_utils "tmp/src/ZeroKnowledge/utils"   // Import Zero Knowledge utility functions
+)
+
+// CreateRotationProof proves knowledge of the old and the new secret of the user under one challenge bound
+// to the rotation nonce the registry issued
+func (z *ZeroKnowledge) CreateRotationProof(oldSecret *zkx_types.SecretKey, newSecret *zkx_types.SecretKey, user string, nonce []byte) (*zkx_models.RotationProof, error) {
+	oldKey, newKey := z.secretScalar(oldSecret), z.secretScalar(newSecret)
+	defer oldKey.Destroy()
+	defer newKey.Destroy()
+	oldSignature, newSignature := z.CreateSignature(oldSecret), z.CreateSignature(newSecret)
+	if bytes.Equal(oldSignature.Signature, newSignature.Signature) {
+		return nil, errors.New("New secret must differ from the old one")
+	}
+
+	rOld, err := z.randomSecretScalar()
+	if err != nil {
+		return nil, err
+	}
+	defer rOld.Destroy()
+	rNew, err := z.randomSecretScalar()
+	if err != nil {
+		return nil, err
+	}
+	defer rNew.Destroy()
+	c := z.rotationChallenge(user, nonce, oldSignature, newSignature, z.secretBaseMult(rOld), z.secretBaseMult(rNew))
+
+	// Both responses share c, so neither proof can be lifted into another rotation
+	cs := z.toScalar(c)
+	mOld := new(zkx_types.Scalar).Sub(rOld, new(zkx_types.Scalar).Mul(cs, oldKey))
+	mNew := new(zkx_types.Scalar).Sub(rNew, new(zkx_types.Scalar).Mul(cs, newKey))
+
+	return &zkx_models.RotationProof{
+		Params: z.Params,
+		Old:    oldSignature,
+		New:    newSignature,
+		User:   user,
+		Nonce:  nonce,
+		C:      zkx_utils.IntToBytes(c),
+		MOld:   mOld.Bytes(),
+		MNew:   mNew.Bytes(),
+	}, nil
+}
+
+// VerifyRotationProof checks that the prover knows both secrets of the rotation
+func (z *ZeroKnowledge) VerifyRotationProof(proof zkx_models.RotationProof) bool {
+	if proof.Old.Service != "" || proof.New.Service != "" {
+		return false // Both halves are proven on the generator, pseudonyms cannot be rotated
+	}
+	oldPoint, err := z.unmarshalPoint(proof.Old.Signature)
+	if err != nil {
+		return false
+	}
+	newPoint, err := z.unmarshalPoint(proof.New.Signature)
+	if err != nil {
+		return false
+	}
+	c := new(big.Int).SetBytes(proof.C)
+	mOld, mNew := new(big.Int).SetBytes(proof.MOld), new(big.Int).SetBytes(proof.MNew)
+	if mOld.Cmp(z.Curve.Params().N) >= 0 || mNew.Cmp(z.Curve.Params().N) >= 0 {
+		return false
+	}
+	base := z.basePoint("")
+	rOld := z.commitment(base, oldPoint, c, mOld)
+	rNew := z.commitment(base, newPoint, c, mNew)
+	return c.Cmp(z.rotationChallenge(proof.User, proof.Nonce, proof.Old, proof.New, rOld, rNew)) == 0
+}
+
+// RotateSignature verifies a rotation proof for the user and swaps the registered signature, the registry
+// consumes the nonce so that the proof cannot be replayed
+func (z *ZeroKnowledge) RotateSignature(registry SignatureRegistry, user string, proof zkx_models.RotationProof) error {
+	if proof.User != user {
+		return errors.New("Rotation proof is for another user")
+	}
+	if !z.VerifyRotationProof(proof) {
+		return errors.New("Invalid rotation proof")
+	}
+	return registry.Swap(zkx_models.RotationEvent{
+		User:      user,
+		Old:       proof.Old,
+		New:       proof.New,
+		Timestamp: time.Now().UTC(),
+	}, proof.Nonce)
+}
+
+// rotationChallenge derives the challenge shared by both halves of a rotation proof
+func (z *ZeroKnowledge) rotationChallenge(user string, nonce []byte, oldSignature zkx_models.ZeroKnowledgeSignature, newSignature zkx_models.ZeroKnowledgeSignature, rOld zkx_models.Point, rNew zkx_models.Point) *big.Int {
+	binding, _ := json.Marshal([]interface{}{"Rotation", user, nonce})
+	return z.Hash(binding, oldSignature.Signature, newSignature.Signature, z.marshalPoint(rOld), z.marshalPoint(rNew))
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"testing"                                 // Import package for testing
+	"time"                                    // Import package for handling time
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+// rotate issues a nonce and rotates the signature of the user from one secret to another
+func rotate(t *testing.T, z *ZeroKnowledge, registry SignatureRegistry, user string, from string, to string) (*zkx_models.RotationProof, error) {
+	t.Helper()
+	nonce, err := registry.RotationNonce(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := z.CreateRotationProof(testSecret(from), testSecret(to), user, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proof, z.RotateSignature(registry, user, *proof)
+}
+
+func TestRotationRoundTrip(t *testing.T) {
+	z := newTestZK(t)
+	registry := NewMemoryRegistry()
+	if err := registry.Register("alice", z.CreateSignature(testSecret("old"))); err != nil {
+		t.Fatal(err)
+	}
+	token, err := z.GenerateJWT(z.CreateSignature(testSecret("old")), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !z.LoginUser(registry, "alice", *z.Sign(testSecret("old"), token)) {
+		t.Fatal("login with the registered signature failed")
+	}
+
+	proof, err := rotate(t, z, registry, "alice", "old", "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := proof.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := zkx_models.RotationProof{}
+	if err := decoded.FromJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	if !z.VerifyRotationProof(decoded) {
+		t.Fatal("decoded rotation proof does not verify")
+	}
+	current, _ := registry.Get("alice")
+	if !sameSignature(current, z.CreateSignature(testSecret("new"))) || len(registry.Events("alice")) != 1 {
+		t.Fatal("registry was not rotated")
+	}
+
+	// Tokens of the replaced signature no longer log in
+	if z.LoginUser(registry, "alice", *z.Sign(testSecret("old"), token)) {
+		t.Fatal("token of the rotated signature logged in")
+	}
+	fresh, err := z.GenerateJWT(current, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !z.LoginUser(registry, "alice", *z.Sign(testSecret("new"), fresh)) {
+		t.Fatal("login with the new signature failed")
+	}
+}
+
+func TestRotationReplay(t *testing.T) {
+	z := newTestZK(t)
+	registry := NewMemoryRegistry()
+	if err := registry.Register("alice", z.CreateSignature(testSecret("a"))); err != nil {
+		t.Fatal(err)
+	}
+	first, err := rotate(t, z, registry, "alice", "a", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rotate(t, z, registry, "alice", "b", "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The key is back to a, the old a to b proof must not apply again
+	if err := z.RotateSignature(registry, "alice", *first); err == nil {
+		t.Fatal("replayed rotation proof was accepted")
+	}
+	if _, err := registry.RotationNonce("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.RotateSignature(registry, "alice", *first); err == nil {
+		t.Fatal("rotation proof over a stale nonce was accepted")
+	}
+	if len(registry.Events("alice")) != 2 {
+		t.Fatal("replay was recorded")
+	}
+}
+
+func TestRotationRejectsForeignProofs(t *testing.T) {
+	z := newTestZK(t)
+	registry := NewMemoryRegistry()
+	for _, user := range []string{"alice", "bob"} {
+		if err := registry.Register(user, z.CreateSignature(testSecret(user))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A proof for one user does not rotate another
+	nonce, err := registry.RotationNonce("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := z.CreateRotationProof(testSecret("bob"), testSecret("new"), "alice", nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := z.RotateSignature(registry, "bob", *proof); err == nil {
+		t.Fatal("rotation proof for another user was accepted")
+	}
+	proof.User = "bob"
+	if err := z.RotateSignature(registry, "bob", *proof); err == nil {
+		t.Fatal("rotation proof with a swapped user was accepted")
+	}
+
+	// Pseudonyms are proven on another base and cannot be rotated
+	proof, err = z.CreateRotationProof(testSecret("bob"), testSecret("new"), "bob", nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof.New.Service = "shop"
+	if z.VerifyRotationProof(*proof) {
+		t.Fatal("rotation to a pseudonym verifies")
+	}
+
+	// Swap compares the whole signature, not only the point
+	event := zkx_models.RotationEvent{User: "alice", Old: z.CreateSignature(testSecret("alice")), New: z.CreateSignature(testSecret("new"))}
+	event.Old.Service = "shop"
+	nonce, err = registry.RotationNonce("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Swap(event, nonce); err == nil {
+		t.Fatal("swap matched a signature of another service")
+	}
+}
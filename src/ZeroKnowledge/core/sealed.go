@@ -0,0 +1,96 @@
+package core
+
+import (
+	"crypto/hmac"                             // Import package for HMAC construction
+	"crypto/sha256"                           // Import SHA-256 cryptographic hash function
+	"encoding/base64"                         // Import package for base64 encoding
+	"strings"                                 // Import package for string manipulation
+	"time"                                    // Import package for handling time
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+	zkx_utils "tmp/src/ZeroKnowledge/utils"   // Import Zero Knowledge utility functions
+)
+
+// A sealed challenge is the base64 content, a dot and the base64 HMAC of the content under the
+// server key. Every replica holding the key can check it without shared state, only the replay
+// filter has to remember answered challenges until they expire.
+
+// sealedRandomSize is the number of random bytes in a sealed challenge
+const sealedRandomSize = 16
+
+// SealedChallengeService issues and verifies stateless challenges
+type SealedChallengeService struct {
+	ZK       *ZeroKnowledge       // Instance the proofs are verified with
+	Key      *zkx_types.SecretKey // HMAC key shared by all replicas
+	Lifetime time.Duration        // How long a challenge can be answered
+	Replays  ReplayStore          // Filter of answered challenges, its window must cover Lifetime
+}
+
+// NewSealedChallengeService creates a service sealing challenges with the key
+func NewSealedChallengeService(z *ZeroKnowledge, key *zkx_types.SecretKey, lifetime time.Duration, replays ReplayStore) *SealedChallengeService {
+	return &SealedChallengeService{ZK: z, Key: key, Lifetime: lifetime, Replays: replays}
+}
+
+// Issue creates a sealed challenge for the session of the user, the client signs it as it is
+func (s *SealedChallengeService) Issue(user string, session string) (string, error) {
+	challenge := zkx_models.SealedChallenge{
+		User:    user,
+		Session: session,
+		Random:  zkx_utils.GenerateSalt(sealedRandomSize),
+		Expires: time.Now().Add(s.Lifetime).Unix(),
+	}
+	content, err := challenge.ToJSON()
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(content)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.seal(encoded)), nil
+}
+
+// Open checks the seal and expiry of a challenge and returns its content
+func (s *SealedChallengeService) Open(sealed string) (*zkx_models.SealedChallenge, error) {
+	encoded, mac, ok := strings.Cut(sealed, ".")
+	if !ok {
+		return nil, zkx_errors.ErrChallengeSeal
+	}
+	tag, err := base64.RawURLEncoding.DecodeString(mac)
+	if err != nil || !hmac.Equal(tag, s.seal(encoded)) {
+		return nil, zkx_errors.ErrChallengeSeal
+	}
+	content, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, zkx_errors.ErrChallengeSeal
+	}
+	challenge := zkx_models.SealedChallenge{}
+	if err := challenge.FromJSON(content); err != nil {
+		return nil, zkx_errors.ErrChallengeSeal
+	}
+	if time.Now().Unix() > challenge.Expires {
+		return nil, zkx_errors.ErrChallengeExpired
+	}
+	return &challenge, nil
+}
+
+// Verify checks a proof over a sealed challenge of the session and records the challenge as answered
+func (s *SealedChallengeService) Verify(user string, session string, loginData zkx_models.ZeroKnowledgeData, signature zkx_models.ZeroKnowledgeSignature) error {
+	challenge, err := s.Open(loginData.Data)
+	if err != nil {
+		return err
+	}
+	if challenge.User != user || challenge.Session != session {
+		return zkx_errors.ErrChallengeBinding
+	}
+	if !s.ZK.Verify(loginData, signature, nil) {
+		return zkx_errors.ErrInvalidProof
+	}
+	fingerprint := sha256.Sum256([]byte(loginData.Data))
+	return s.Replays.Remember(fingerprint[:], time.Now())
+}
+
+// seal computes the HMAC of the encoded content
+func (s *SealedChallengeService) seal(encoded string) []byte {
+	mac := hmac.New(sha256.New, s.Key.Bytes())
+	mac.Write([]byte("SealedChallenge/" + encoded))
+	return mac.Sum(nil)
+}
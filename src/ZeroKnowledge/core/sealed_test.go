@@ -0,0 +1,86 @@
+package core
+
+import (
+	"errors"                                  // Import package for error handling
+	"strings"                                 // Import package for string manipulation
+	"testing"                                 // Import package for testing
+	"time"                                    // Import package for handling time
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+)
+
+// newSealedService creates a sealed challenge service with a test key and replay filter
+func newSealedService(z *ZeroKnowledge, key string, lifetime time.Duration) *SealedChallengeService {
+	return NewSealedChallengeService(z, testSecret(key), lifetime, NewMemoryReplayStore(time.Minute, 16))
+}
+
+func TestSealedChallengeRoundTrip(t *testing.T) {
+	z := newTestZK(t)
+	signature := z.CreateSignature(testSecret("alice"))
+	issuer := newSealedService(z, "sealing key", time.Minute)
+	sealed, err := issuer.Issue("alice", "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	login := *z.Sign(testSecret("alice"), sealed)
+
+	// Any replica holding the key verifies, but each shares the replay filter it was given
+	replica := newSealedService(z, "sealing key", time.Minute)
+	replica.Replays = issuer.Replays
+	if err := replica.Verify("alice", "session", login, signature); err != nil {
+		t.Fatal(err)
+	}
+	if err := issuer.Verify("alice", "session", login, signature); !errors.Is(err, zkx_errors.ErrProofReplayed) {
+		t.Fatalf("replayed answer: %v", err)
+	}
+}
+
+func TestSealedChallengeRejections(t *testing.T) {
+	z := newTestZK(t)
+	signature := z.CreateSignature(testSecret("alice"))
+	service := newSealedService(z, "sealing key", time.Minute)
+	sealed, err := service.Issue("alice", "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	login := *z.Sign(testSecret("alice"), sealed)
+
+	if err := service.Verify("bob", "session", login, signature); !errors.Is(err, zkx_errors.ErrChallengeBinding) {
+		t.Fatalf("challenge of another user: %v", err)
+	}
+	if err := service.Verify("alice", "other session", login, signature); !errors.Is(err, zkx_errors.ErrChallengeBinding) {
+		t.Fatalf("challenge of another session: %v", err)
+	}
+	if err := service.Verify("alice", "session", login, z.CreateSignature(testSecret("mallory"))); !errors.Is(err, zkx_errors.ErrInvalidProof) {
+		t.Fatalf("answer of another identity: %v", err)
+	}
+	if err := newSealedService(z, "other key", time.Minute).Verify("alice", "session", login, signature); !errors.Is(err, zkx_errors.ErrChallengeSeal) {
+		t.Fatalf("challenge sealed with another key: %v", err)
+	}
+
+	// Changing a single character of the content breaks the seal
+	content, mac, _ := strings.Cut(sealed, ".")
+	altered := []byte(content)
+	altered[0] ^= 1
+	for _, forged := range []string{string(altered) + "." + mac, content, content + "." + mac[1:]} {
+		if _, err := service.Open(forged); !errors.Is(err, zkx_errors.ErrChallengeSeal) {
+			t.Fatalf("forged challenge %q: %v", forged, err)
+		}
+	}
+
+	// The rejected answers above were not remembered
+	if err := service.Verify("alice", "session", login, signature); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSealedChallengeExpires(t *testing.T) {
+	z := newTestZK(t)
+	service := newSealedService(z, "sealing key", -2*time.Second)
+	sealed, err := service.Issue("alice", "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Open(sealed); !errors.Is(err, zkx_errors.ErrChallengeExpired) {
+		t.Fatalf("expired challenge: %v", err)
+	}
+}
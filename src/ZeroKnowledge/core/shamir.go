@@ -0,0 +1,173 @@
+package core
+
+import (
+	"bytes"                                   // Import package for byte slice comparison
+	"crypto/sha256"                           // Import SHA-256 cryptographic hash function
+	"errors"                                  // Import package for error handling
+	"math/big"                                // Import package for big integer arithmetic
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+	zkx_utils "tmp/src/ZeroKnowledge/utils"   // Import Zero Knowledge utility functions
+)
+
+// SplitSecret splits the identity scalar of a secret into count shares, any threshold of which recover it
+func (z *ZeroKnowledge) SplitSecret(secret *zkx_types.SecretKey, threshold int, count int) (*zkx_models.ShamirBackup, []zkx_models.ShamirShare, error) {
+	if threshold < 1 || count < threshold {
+		return nil, nil, errors.New("Invalid share threshold")
+	}
+
+	// f(0) is the identity scalar, the other coefficients are random
+	coefficients := []*zkx_types.Scalar{z.secretScalar(secret)}
+	for i := 1; i < threshold; i++ {
+		a, err := z.randomSecretScalar()
+		if err != nil {
+			return nil, nil, err
+		}
+		coefficients = append(coefficients, a)
+	}
+
+	// Commit to every coefficient so that shares can be verified on their own
+	commitments := make([][]byte, threshold)
+	for i, a := range coefficients {
+		commitments[i] = z.marshalPoint(z.secretBaseMult(a))
+	}
+
+	shares := make([]zkx_models.ShamirShare, count)
+	for i := range shares {
+		index := i + 1
+		value := z.evalPolynomial(coefficients, index).Bytes()
+		shares[i] = zkx_models.ShamirShare{
+			Index:    index,
+			Value:    value,
+			Checksum: shareChecksum(index, value),
+		}
+	}
+
+	// Wipe the coefficients once the shares exist
+	for _, a := range coefficients {
+		a.Destroy()
+	}
+
+	return &zkx_models.ShamirBackup{
+		Params:      z.Params,
+		Threshold:   threshold,
+		Commitments: commitments,
+	}, shares, nil
+}
+
+// ImportShare decodes a JSON share and rejects it if the checksum does not match
+func ImportShare(data []byte) (*zkx_models.ShamirShare, error) {
+	share := zkx_models.ShamirShare{}
+	if err := share.FromJSON(data); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(share.Checksum, shareChecksum(share.Index, share.Value)) {
+		return nil, errors.New("Share checksum mismatch")
+	}
+	return &share, nil
+}
+
+// VerifyShare checks a share against the Feldman commitments of the backup
+func (z *ZeroKnowledge) VerifyShare(share zkx_models.ShamirShare, backup zkx_models.ShamirBackup) bool {
+	if share.Index < 1 || !bytes.Equal(share.Checksum, shareChecksum(share.Index, share.Value)) {
+		return false
+	}
+
+	// s_i*G must equal the sum of C_j * i^j
+	N := z.Curve.Params().N
+	index := big.NewInt(int64(share.Index))
+	power := big.NewInt(1)
+	var x, y *big.Int
+	for _, commitment := range backup.Commitments {
+		point, err := z.unmarshalPoint(commitment)
+		if err != nil {
+			return false
+		}
+		px, py := z.Curve.ScalarMult(point.X, point.Y, power.Bytes())
+		if x == nil {
+			x, y = px, py
+		} else {
+			x, y = z.Curve.Add(x, y, px, py)
+		}
+		power.Mod(power.Mul(power, index), N)
+	}
+	if x == nil {
+		return false
+	}
+	sx, sy := z.Curve.ScalarBaseMult(share.Value)
+	return sx.Cmp(x) == 0 && sy.Cmp(y) == 0
+}
+
+// CombineShares recovers the identity scalar from at least threshold verified shares
+func (z *ZeroKnowledge) CombineShares(shares []zkx_models.ShamirShare, backup zkx_models.ShamirBackup) (*zkx_types.Scalar, error) {
+	if backup.Threshold < 1 || len(backup.Commitments) != backup.Threshold {
+		return nil, errors.New("Malformed share backup")
+	}
+
+	// Skip corrupt shares, any threshold of the valid ones recovers the secret
+	var valid []zkx_models.ShamirShare
+	seen := make(map[int]bool)
+	for _, share := range shares {
+		if len(valid) == backup.Threshold {
+			break
+		}
+		if seen[share.Index] || !z.VerifyShare(share, backup) {
+			continue
+		}
+		seen[share.Index] = true
+		valid = append(valid, share)
+	}
+	if len(valid) < backup.Threshold {
+		return nil, errors.New("Not enough valid shares")
+	}
+	shares = valid
+
+	// Lagrange interpolation at zero, the coefficients only depend on the public indices
+	N := z.Curve.Params().N
+	key := z.toScalar(new(big.Int))
+	for i, share := range shares {
+		numerator, denominator := big.NewInt(1), big.NewInt(1)
+		for j, other := range shares {
+			if i == j {
+				continue
+			}
+			numerator.Mul(numerator, big.NewInt(int64(other.Index)))
+			denominator.Mul(denominator, big.NewInt(int64(other.Index-share.Index)))
+		}
+		lagrange := z.toScalar(numerator.Mul(numerator, denominator.ModInverse(denominator.Mod(denominator, N), N)))
+		value, err := zkx_types.NewScalar(z.scalarOrder(), share.Value)
+		if err != nil {
+			return nil, err
+		}
+		key.Add(key, value.Mul(lagrange, value))
+		value.Destroy()
+	}
+
+	if !bytes.Equal(z.marshalPoint(z.secretBaseMult(key)), backup.Commitments[0]) {
+		key.Destroy()
+		return nil, errors.New("Recovered secret does not match the backup")
+	}
+	return key, nil
+}
+
+// CheckRecoveredKey confirms that a recovered identity scalar belongs to the registered signature
+func (z *ZeroKnowledge) CheckRecoveredKey(key *zkx_types.Scalar, signature zkx_models.ZeroKnowledgeSignature) bool {
+	return bytes.Equal(z.marshalPoint(z.secretBaseMult(key)), signature.Signature)
+}
+
+// evalPolynomial evaluates the polynomial with the given coefficients at x modulo the curve order
+func (z *ZeroKnowledge) evalPolynomial(coefficients []*zkx_types.Scalar, x int) *zkx_types.Scalar {
+	point := z.toScalar(big.NewInt(int64(x)))
+	result := z.toScalar(new(big.Int))
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result.Mul(result, point)
+		result.Add(result, coefficients[i])
+	}
+	return result
+}
+
+// shareChecksum computes the first four bytes of SHA-256 over the index and value of a share
+func shareChecksum(index int, value []byte) []byte {
+	hash := sha256.Sum256(append(zkx_utils.IntToBytes(big.NewInt(int64(index))), value...))
+	return hash[:4]
+}
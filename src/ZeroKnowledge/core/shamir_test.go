@@ -0,0 +1,113 @@
+package core
+
+import (
+	"testing"                                 // Import package for testing
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+func TestShamirRoundTrip(t *testing.T) {
+	z := newTestZK(t)
+	secret := testSecret("alice")
+	backup, shares, err := z.SplitSecret(secret, 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, share := range shares {
+		data, err := share.ToJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ImportShare(data); err != nil {
+			t.Fatal(err)
+		}
+		if !z.VerifyShare(share, *backup) {
+			t.Fatalf("share %d does not verify", share.Index)
+		}
+	}
+	key, err := z.CombineShares([]zkx_models.ShamirShare{shares[4], shares[1], shares[2]}, *backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !z.CheckRecoveredKey(key, z.CreateSignature(secret)) {
+		t.Fatal("recovered key does not belong to the signature")
+	}
+}
+
+func TestShamirRejectsBadShares(t *testing.T) {
+	z := newTestZK(t)
+	backup, shares, err := z.SplitSecret(testSecret("alice"), 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := z.CombineShares(shares[:1], *backup); err == nil {
+		t.Fatal("combined fewer shares than the threshold")
+	}
+	if _, err := z.CombineShares([]zkx_models.ShamirShare{shares[0], shares[0]}, *backup); err == nil {
+		t.Fatal("combined a duplicated share")
+	}
+
+	tampered := shares[1]
+	tampered.Value = append([]byte(nil), tampered.Value...)
+	tampered.Value[len(tampered.Value)-1] ^= 1
+	if z.VerifyShare(tampered, *backup) {
+		t.Fatal("tampered share verifies")
+	}
+	tampered.Checksum = shareChecksum(tampered.Index, tampered.Value)
+	if z.VerifyShare(tampered, *backup) {
+		t.Fatal("tampered share with a fixed checksum verifies")
+	}
+	if _, err := z.CombineShares([]zkx_models.ShamirShare{shares[0], tampered}, *backup); err == nil {
+		t.Fatal("combined a tampered share")
+	}
+	data, _ := shares[0].ToJSON()
+	data[len(data)-3] ^= 1
+	if _, err := ImportShare(data); err == nil {
+		t.Fatal("imported a corrupted share")
+	}
+}
+
+// Regression: a backup with a non-positive threshold or missing commitments used to panic
+func TestCombineSharesMalformedBackup(t *testing.T) {
+	z := newTestZK(t)
+	backup, shares, err := z.SplitSecret(testSecret("alice"), 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	malformed := []zkx_models.ShamirBackup{
+		{Params: backup.Params, Threshold: 0, Commitments: backup.Commitments},
+		{Params: backup.Params, Threshold: -1, Commitments: backup.Commitments},
+		{Params: backup.Params, Threshold: 0},
+		{Params: backup.Params, Threshold: 2, Commitments: backup.Commitments[:1]},
+		{Params: backup.Params, Threshold: 1, Commitments: backup.Commitments},
+	}
+	for i, bad := range malformed {
+		if _, err := z.CombineShares(shares, bad); err == nil {
+			t.Fatalf("malformed backup %d was accepted", i)
+		}
+	}
+}
+
+func TestCombineSharesSkipsCorruptShares(t *testing.T) {
+	z := newTestZK(t)
+	secret := testSecret("alice")
+	backup, shares, err := z.SplitSecret(secret, 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := shares[0]
+	tampered.Value = append([]byte(nil), tampered.Value...)
+	tampered.Value[0] ^= 1
+	tampered.Checksum = shareChecksum(tampered.Index, tampered.Value)
+
+	// A corrupt share and a repeated one ahead of enough valid shares do not stop recovery
+	key, err := z.CombineShares([]zkx_models.ShamirShare{tampered, shares[1], shares[1], shares[3]}, *backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !z.CheckRecoveredKey(key, z.CreateSignature(secret)) {
+		t.Fatal("recovered key does not belong to the signature")
+	}
+	if _, err := z.CombineShares([]zkx_models.ShamirShare{tampered, shares[1]}, *backup); err == nil {
+		t.Fatal("combined fewer valid shares than the threshold")
+	}
+}
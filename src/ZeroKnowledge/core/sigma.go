@@ -0,0 +1,556 @@
+package core
+
+import (
+	"bytes"                                   // Import package for byte slice comparison
+	"encoding/binary"                         // Import package for big-endian integers
+	"errors"                                  // Import package for error handling
+	"fmt"                                     // Import package for formatted I/O
+	"math/big"                                // Import package for big integer arithmetic
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+	zkx_utils "tmp/src/ZeroKnowledge/utils"   // Import Zero Knowledge utility functions
+)
+
+// Compound statements are trees of linear relations joined by AND and OR. A relation proves knowledge
+// of witnesses with Y = x1*B1 + ... + xn*Bn for each of its equations, using one response per witness.
+// The prover commits to every node in statement order, hashes the statement, the commitments and the
+// data into one Fiat-Shamir challenge and answers it. AND passes the challenge to all parts, OR splits
+// it so that the prover can simulate every part but one (Cramer, Damgard and Schoenmakers). Witnesses
+// are only linked within a relation, put equations in the same relation to prove they share a secret,
+// parts joined by AND must use distinct witness names while the alternatives of an OR may repeat them.
+// Every scalar of a proof is encoded at the byte length of the curve order, and fields a node does not
+// use must be empty, so each proof has exactly one encoding.
+
+// Generator returns the generator of the curve, the base of every signature
+func (z *ZeroKnowledge) Generator() zkx_models.Point {
+	return z.basePoint("")
+}
+
+// HashBase returns a base point derived from the label, nobody knows its logarithm to any other base
+func (z *ZeroKnowledge) HashBase(label string) zkx_models.Point {
+	x, y := zkx_utils.HashToCurve(z.Curve, []byte("Sigma"), []byte(label))
+	return zkx_models.Point{X: x, Y: y}
+}
+
+// SecretWitness returns the identity scalar of a secret, the logarithm of its signature to the generator
+func (z *ZeroKnowledge) SecretWitness(secret *zkx_types.SecretKey) *zkx_types.Scalar {
+	return z.secretScalar(secret)
+}
+
+// RandomWitness draws a random witness, such as the blinding factor of a commitment
+func (z *ZeroKnowledge) RandomWitness() (*zkx_types.Scalar, error) {
+	return z.randomSecretScalar()
+}
+
+// WitnessPoint computes witness*base, the public point of a term
+func (z *ZeroKnowledge) WitnessPoint(base zkx_models.Point, witness *zkx_types.Scalar) zkx_models.Point {
+	return z.secretMult(base, witness)
+}
+
+// SumPoints adds points, for example the terms of a Pedersen commitment
+func (z *ZeroKnowledge) SumPoints(points ...zkx_models.Point) zkx_models.Point {
+	sum := zkx_models.Point{}
+	for _, point := range points {
+		sum = z.addPoints(sum, point)
+	}
+	return sum
+}
+
+// Relation states knowledge of witnesses that satisfy every equation
+func Relation(equations ...zkx_models.SigmaEquation) zkx_models.SigmaStatement {
+	return zkx_models.SigmaStatement{Kind: zkx_models.SigmaRelation, Equations: equations}
+}
+
+// Equation writes a public point as a sum of terms
+func (z *ZeroKnowledge) Equation(public zkx_models.Point, terms ...zkx_models.SigmaTerm) zkx_models.SigmaEquation {
+	return zkx_models.SigmaEquation{Public: z.marshalPoint(public), Terms: terms}
+}
+
+// Term multiplies the named witness by a base
+func (z *ZeroKnowledge) Term(witness string, base zkx_models.Point) zkx_models.SigmaTerm {
+	return zkx_models.SigmaTerm{Witness: witness, Base: z.marshalPoint(base)}
+}
+
+// DLog states knowledge of x with public = x*base
+func (z *ZeroKnowledge) DLog(witness string, base zkx_models.Point, public zkx_models.Point) zkx_models.SigmaStatement {
+	return Relation(z.Equation(public, z.Term(witness, base)))
+}
+
+// Representation states knowledge of x1..xn with public = x1*B1 + ... + xn*Bn
+func (z *ZeroKnowledge) Representation(public zkx_models.Point, terms ...zkx_models.SigmaTerm) zkx_models.SigmaStatement {
+	return Relation(z.Equation(public, terms...))
+}
+
+// Equality states knowledge of one x with publics[i] = x*bases[i] for every i
+func (z *ZeroKnowledge) Equality(witness string, bases []zkx_models.Point, publics []zkx_models.Point) (zkx_models.SigmaStatement, error) {
+	if len(bases) != len(publics) || len(bases) == 0 {
+		return zkx_models.SigmaStatement{}, errors.New("Equality needs one public point per base")
+	}
+	equations := make([]zkx_models.SigmaEquation, len(bases))
+	for i := range bases {
+		equations[i] = z.Equation(publics[i], z.Term(witness, bases[i]))
+	}
+	return Relation(equations...), nil
+}
+
+// And states that every part holds
+func And(parts ...zkx_models.SigmaStatement) zkx_models.SigmaStatement {
+	return zkx_models.SigmaStatement{Kind: zkx_models.SigmaAnd, Children: parts}
+}
+
+// Or states that at least one part holds, without revealing which
+func Or(parts ...zkx_models.SigmaStatement) zkx_models.SigmaStatement {
+	return zkx_models.SigmaStatement{Kind: zkx_models.SigmaOr, Children: parts}
+}
+
+// sigmaProver holds the state of one proof while it is being created
+type sigmaProver struct {
+	z           *ZeroKnowledge               // Instance the proof is created with
+	witnesses   map[string]*zkx_types.Scalar // Known witnesses by name
+	commitments [][]byte                     // Marshalled commitments in statement order
+	secrets     []*zkx_types.Scalar          // Nonces to destroy once the proof is done
+}
+
+// sigmaState is the prover side of one statement node
+type sigmaState struct {
+	statement zkx_models.SigmaStatement // Node being proven
+	proof     zkx_models.SigmaNode      // Proof of the node, completed by respond
+	simulated bool                      // Whether the proof was simulated for a fixed challenge
+	names     []string                  // Witnesses of a relation in order of appearance
+	nonces    []*zkx_types.Scalar       // Nonces of a real relation, one per witness
+	children  []*sigmaState             // States of the parts of an AND or OR
+}
+
+// ProveStatement proves a compound statement over the data, witnesses only need to satisfy one part of every OR
+func (z *ZeroKnowledge) ProveStatement(statement zkx_models.SigmaStatement, witnesses map[string]*zkx_types.Scalar, data interface{}) (*zkx_models.SigmaProof, error) {
+	if err := z.checkStatement(statement); err != nil {
+		return nil, err
+	}
+	if !z.canProve(statement, witnesses) {
+		return nil, errors.New("Witnesses do not satisfy the statement")
+	}
+	p := &sigmaProver{z: z, witnesses: witnesses}
+	defer func() {
+		for _, secret := range p.secrets {
+			secret.Destroy()
+		}
+	}()
+
+	state, err := p.commit(statement)
+	if err != nil {
+		return nil, err
+	}
+	payload := fmt.Sprint(data)
+	c := z.sigmaChallenge(statement, p.commitments, payload)
+	p.respond(state, z.toScalar(c))
+
+	return &zkx_models.SigmaProof{
+		Params:    z.Params,
+		Data:      payload,
+		Challenge: z.toScalar(c).Bytes(),
+		Root:      state.tree(),
+	}, nil
+}
+
+// VerifyStatement checks a proof of a compound statement, the verifier builds the statement itself
+func (z *ZeroKnowledge) VerifyStatement(statement zkx_models.SigmaStatement, proof zkx_models.SigmaProof) bool {
+	if z.checkStatement(statement) != nil {
+		return false
+	}
+	c, ok := z.canonicalScalar(proof.Challenge)
+	if !ok {
+		return false
+	}
+	var commitments [][]byte
+	if !z.verifyNode(statement, proof.Root, c, &commitments) {
+		return false
+	}
+	return c.Cmp(z.sigmaChallenge(statement, commitments, proof.Data)) == 0
+}
+
+// commit creates the commitments of a node the prover knows witnesses for
+func (p *sigmaProver) commit(statement zkx_models.SigmaStatement) (*sigmaState, error) {
+	state := &sigmaState{statement: statement}
+	switch statement.Kind {
+	case zkx_models.SigmaRelation:
+		state.names = relationWitnesses(statement)
+		nonces := make(map[string]*zkx_types.Scalar, len(state.names))
+		for _, name := range state.names {
+			nonce, err := p.z.randomSecretScalar()
+			if err != nil {
+				return nil, err
+			}
+			p.secrets = append(p.secrets, nonce)
+			state.nonces = append(state.nonces, nonce)
+			nonces[name] = nonce
+		}
+		for _, equation := range statement.Equations {
+			R := zkx_models.Point{}
+			for _, term := range equation.Terms {
+				base, _ := p.z.unmarshalPoint(term.Base)
+				R = p.z.addPoints(R, p.z.secretMult(base, nonces[term.Witness]))
+			}
+			p.commitments = append(p.commitments, p.z.marshalPoint(R))
+		}
+	case zkx_models.SigmaAnd:
+		for _, child := range statement.Children {
+			childState, err := p.commit(child)
+			if err != nil {
+				return nil, err
+			}
+			state.children = append(state.children, childState)
+		}
+	case zkx_models.SigmaOr:
+		known := -1
+		for i, child := range statement.Children {
+			var childState *sigmaState
+			var err error
+			if known < 0 && p.z.canProve(child, p.witnesses) {
+				known = i
+				childState, err = p.commit(child)
+			} else {
+				childState, err = p.simulateRandom(child)
+			}
+			if err != nil {
+				return nil, err
+			}
+			state.children = append(state.children, childState)
+		}
+	}
+	return state, nil
+}
+
+// simulateRandom simulates a node for a random challenge, which becomes the challenge of the node
+func (p *sigmaProver) simulateRandom(statement zkx_models.SigmaStatement) (*sigmaState, error) {
+	c, err := p.z.randomSecretScalar()
+	if err != nil {
+		return nil, err
+	}
+	state, err := p.simulate(statement, c)
+	if err != nil {
+		return nil, err
+	}
+	state.proof.Challenge = c.Bytes()
+	return state, nil
+}
+
+// simulate creates an accepting transcript of a node for a fixed challenge, without any witness
+func (p *sigmaProver) simulate(statement zkx_models.SigmaStatement, c *zkx_types.Scalar) (*sigmaState, error) {
+	state := &sigmaState{statement: statement, simulated: true}
+	switch statement.Kind {
+	case zkx_models.SigmaRelation:
+		names := relationWitnesses(statement)
+		responses := make(map[string]*big.Int, len(names))
+		for _, name := range names {
+			m, err := p.z.randomSecretScalar()
+			if err != nil {
+				return nil, err
+			}
+			state.proof.Responses = append(state.proof.Responses, m.Bytes())
+			responses[name] = new(big.Int).SetBytes(m.Bytes())
+		}
+		challenge := new(big.Int).SetBytes(c.Bytes())
+		for _, equation := range statement.Equations {
+			p.commitments = append(p.commitments, p.z.marshalPoint(p.z.equationCommitment(equation, responses, challenge)))
+		}
+	case zkx_models.SigmaAnd:
+		for _, child := range statement.Children {
+			childState, err := p.simulate(child, c)
+			if err != nil {
+				return nil, err
+			}
+			state.children = append(state.children, childState)
+		}
+	case zkx_models.SigmaOr:
+		rest, _ := zkx_types.NewScalar(p.z.scalarOrder(), c.Bytes()) // Challenge left for the last part
+		for i, child := range statement.Children {
+			var childState *sigmaState
+			var err error
+			if i < len(statement.Children)-1 {
+				childState, err = p.simulateRandom(child)
+				if err == nil {
+					challenge, _ := zkx_types.NewScalar(p.z.scalarOrder(), childState.proof.Challenge)
+					rest.Sub(rest, challenge)
+				}
+			} else {
+				childState, err = p.simulate(child, rest)
+				if err == nil {
+					childState.proof.Challenge = rest.Bytes()
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+			state.children = append(state.children, childState)
+		}
+	}
+	return state, nil
+}
+
+// respond answers the challenge of a node the prover committed to
+func (p *sigmaProver) respond(state *sigmaState, c *zkx_types.Scalar) {
+	if state.simulated {
+		return
+	}
+	switch state.statement.Kind {
+	case zkx_models.SigmaRelation:
+		// m = r - c*x, like the response of a Schnorr proof
+		for i, name := range state.names {
+			m := new(zkx_types.Scalar).Sub(state.nonces[i], new(zkx_types.Scalar).Mul(c, p.witnesses[name]))
+			state.proof.Responses = append(state.proof.Responses, m.Bytes())
+		}
+	case zkx_models.SigmaAnd:
+		for _, child := range state.children {
+			p.respond(child, c)
+		}
+	case zkx_models.SigmaOr:
+		// The real part gets whatever the simulated parts leave of the challenge
+		rest, _ := zkx_types.NewScalar(p.z.scalarOrder(), c.Bytes())
+		var known *sigmaState
+		for _, child := range state.children {
+			if child.simulated {
+				challenge, _ := zkx_types.NewScalar(p.z.scalarOrder(), child.proof.Challenge)
+				rest.Sub(rest, challenge)
+			} else {
+				known = child
+			}
+		}
+		known.proof.Challenge = rest.Bytes()
+		p.respond(known, rest)
+	}
+}
+
+// tree assembles the proof of a node from the states of its parts
+func (state *sigmaState) tree() zkx_models.SigmaNode {
+	node := state.proof
+	for _, child := range state.children {
+		node.Children = append(node.Children, child.tree())
+	}
+	return node
+}
+
+// verifyNode recomputes the commitments of a node for its challenge and checks the shape of its proof,
+// only the parts of an OR carry a challenge and the OR takes it off before their nodes are verified
+func (z *ZeroKnowledge) verifyNode(statement zkx_models.SigmaStatement, node zkx_models.SigmaNode, c *big.Int, commitments *[][]byte) bool {
+	if len(node.Challenge) != 0 {
+		return false
+	}
+	switch statement.Kind {
+	case zkx_models.SigmaRelation:
+		names := relationWitnesses(statement)
+		if len(node.Responses) != len(names) || len(node.Children) != 0 {
+			return false
+		}
+		responses := make(map[string]*big.Int, len(names))
+		for i, name := range names {
+			response, ok := z.canonicalScalar(node.Responses[i])
+			if !ok {
+				return false
+			}
+			responses[name] = response
+		}
+		for _, equation := range statement.Equations {
+			*commitments = append(*commitments, z.marshalPoint(z.equationCommitment(equation, responses, c)))
+		}
+		return true
+	case zkx_models.SigmaAnd:
+		if len(node.Children) != len(statement.Children) || len(node.Responses) != 0 {
+			return false
+		}
+		for i, child := range statement.Children {
+			if !z.verifyNode(child, node.Children[i], c, commitments) {
+				return false
+			}
+		}
+		return true
+	case zkx_models.SigmaOr:
+		if len(node.Children) != len(statement.Children) || len(node.Responses) != 0 {
+			return false
+		}
+		sum := new(big.Int)
+		for i, child := range statement.Children {
+			part := node.Children[i]
+			challenge, ok := z.canonicalScalar(part.Challenge)
+			part.Challenge = nil
+			if !ok || !z.verifyNode(child, part, challenge, commitments) {
+				return false
+			}
+			sum.Add(sum, challenge)
+		}
+		return sum.Mod(sum, z.Curve.Params().N).Cmp(c) == 0
+	default:
+		return false
+	}
+}
+
+// canonicalScalar decodes a scalar of a proof, which must be below the order and exactly as long as Scalar.Bytes
+func (z *ZeroKnowledge) canonicalScalar(b []byte) (*big.Int, bool) {
+	N := z.Curve.Params().N
+	value := new(big.Int).SetBytes(b)
+	return value, len(b) == (N.BitLen()+7)/8 && value.Cmp(N) < 0
+}
+
+// equationCommitment recomputes the commitment of an equation, the sum of m*B over its terms plus c*Y
+func (z *ZeroKnowledge) equationCommitment(equation zkx_models.SigmaEquation, responses map[string]*big.Int, c *big.Int) zkx_models.Point {
+	public, _ := z.unmarshalPoint(equation.Public)
+	R := z.scalarMult(public, c)
+	for _, term := range equation.Terms {
+		base, _ := z.unmarshalPoint(term.Base)
+		R = z.addPoints(R, z.scalarMult(base, responses[term.Witness]))
+	}
+	return R
+}
+
+// checkStatement makes sure a statement is well formed and all its points are on the curve
+func (z *ZeroKnowledge) checkStatement(statement zkx_models.SigmaStatement) error {
+	switch statement.Kind {
+	case zkx_models.SigmaRelation:
+		if len(statement.Equations) == 0 || len(statement.Children) != 0 {
+			return errors.New("Relation needs equations and no parts")
+		}
+		for _, equation := range statement.Equations {
+			if len(equation.Terms) == 0 {
+				return errors.New("Equation needs terms")
+			}
+			if _, err := z.unmarshalPoint(equation.Public); err != nil {
+				return err
+			}
+			for _, term := range equation.Terms {
+				if term.Witness == "" {
+					return errors.New("Term needs a witness name")
+				}
+				if _, err := z.unmarshalPoint(term.Base); err != nil {
+					return err
+				}
+			}
+		}
+	case zkx_models.SigmaAnd, zkx_models.SigmaOr:
+		if len(statement.Children) == 0 || len(statement.Equations) != 0 {
+			return errors.New("Composition needs parts and no equations")
+		}
+		for _, child := range statement.Children {
+			if err := z.checkStatement(child); err != nil {
+				return err
+			}
+		}
+
+		// Parts proven together must not share witness names, they would look linked without being so
+		if statement.Kind == zkx_models.SigmaAnd {
+			seen := make(map[string]bool)
+			for _, child := range statement.Children {
+				names := statementWitnesses(child)
+				for name := range names {
+					if seen[name] {
+						return errors.New("Witness appears in more than one relation, put its equations in one relation")
+					}
+				}
+				for name := range names {
+					seen[name] = true
+				}
+			}
+		}
+	default:
+		return errors.New("Unknown statement kind")
+	}
+	return nil
+}
+
+// canProve reports whether the witnesses satisfy a statement, an OR is satisfied by any of its parts
+func (z *ZeroKnowledge) canProve(statement zkx_models.SigmaStatement, witnesses map[string]*zkx_types.Scalar) bool {
+	switch statement.Kind {
+	case zkx_models.SigmaRelation:
+		for _, name := range relationWitnesses(statement) {
+			if witnesses[name] == nil {
+				return false
+			}
+		}
+		for _, equation := range statement.Equations {
+			sum := zkx_models.Point{}
+			for _, term := range equation.Terms {
+				base, _ := z.unmarshalPoint(term.Base)
+				sum = z.addPoints(sum, z.secretMult(base, witnesses[term.Witness]))
+			}
+			if !bytes.Equal(z.marshalPoint(sum), equation.Public) {
+				return false
+			}
+		}
+		return true
+	case zkx_models.SigmaAnd:
+		for _, child := range statement.Children {
+			if !z.canProve(child, witnesses) {
+				return false
+			}
+		}
+		return true
+	case zkx_models.SigmaOr:
+		for _, child := range statement.Children {
+			if z.canProve(child, witnesses) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// relationWitnesses lists the witness names of a relation in order of first appearance
+func relationWitnesses(statement zkx_models.SigmaStatement) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, equation := range statement.Equations {
+		for _, term := range equation.Terms {
+			if !seen[term.Witness] {
+				seen[term.Witness] = true
+				names = append(names, term.Witness)
+			}
+		}
+	}
+	return names
+}
+
+// statementWitnesses collects the witness names of every relation of a statement
+func statementWitnesses(statement zkx_models.SigmaStatement) map[string]bool {
+	names := make(map[string]bool)
+	for _, name := range relationWitnesses(statement) {
+		names[name] = true
+	}
+	for _, child := range statement.Children {
+		for name := range statementWitnesses(child) {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// sigmaChallenge derives the Fiat-Shamir challenge from the statement, the commitments and the data
+func (z *ZeroKnowledge) sigmaChallenge(statement zkx_models.SigmaStatement, commitments [][]byte, data string) *big.Int {
+	transcript := append([]interface{}{"Sigma"}, describeStatement(statement)...)
+	transcript = append(transcript, sigmaLength(len(commitments)))
+	for _, commitment := range commitments {
+		transcript = append(transcript, sigmaLength(len(commitment)), commitment)
+	}
+	return z.Hash(append(transcript, sigmaLength(len(data)), data)...)
+}
+
+// describeStatement encodes a statement for the transcript, every count and every value of variable length is
+// preceded by its 8-byte length so that no two statements collide
+func describeStatement(statement zkx_models.SigmaStatement) []interface{} {
+	values := []interface{}{
+		sigmaLength(len(statement.Kind)), statement.Kind,
+		sigmaLength(len(statement.Equations)), sigmaLength(len(statement.Children)),
+	}
+	for _, equation := range statement.Equations {
+		values = append(values, sigmaLength(len(equation.Public)), equation.Public, sigmaLength(len(equation.Terms)))
+		for _, term := range equation.Terms {
+			values = append(values, sigmaLength(len(term.Witness)), term.Witness, sigmaLength(len(term.Base)), term.Base)
+		}
+	}
+	for _, child := range statement.Children {
+		values = append(values, describeStatement(child)...)
+	}
+	return values
+}
+
+// sigmaLength encodes a length or count in a fixed 8 bytes, IntToBytes would drop the zeros of small values
+func sigmaLength(n int) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(n))
+}
@@ -0,0 +1,200 @@
+package core
+
+import (
+	"math/big"                                // Import package for big integer arithmetic
+	"testing"                                 // Import package for testing
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+)
+
+// sigmaFixture holds two identities and a Pedersen commitment to the first one
+type sigmaFixture struct {
+	z         *ZeroKnowledge
+	witnesses map[string]*zkx_types.Scalar
+	alice     zkx_models.SigmaStatement // DLog of alice
+	bob       zkx_models.SigmaStatement // DLog of bob
+	carol     zkx_models.SigmaStatement // DLog of carol, whose witness the prover knows as well
+	opening   zkx_models.SigmaStatement // Opening of the commitment, sharing the witness of alice
+}
+
+// newSigmaFixture creates the statements, the prover knows the witnesses of alice and carol but not of bob
+func newSigmaFixture(t *testing.T) *sigmaFixture {
+	t.Helper()
+	z := newTestZK(t)
+	x := z.SecretWitness(testSecret("alice"))
+	r, err := z.RandomWitness()
+	if err != nil {
+		t.Fatal(err)
+	}
+	G, H := z.Generator(), z.HashBase("blinding")
+	commitment := z.SumPoints(z.WitnessPoint(G, x), z.WitnessPoint(H, r))
+	alice := z.DLog("x", G, z.WitnessPoint(G, x))
+	w := z.SecretWitness(testSecret("carol"))
+	return &sigmaFixture{
+		z:         z,
+		witnesses: map[string]*zkx_types.Scalar{"x": x, "r": r, "w": w},
+		alice:     alice,
+		bob:       z.DLog("y", G, z.WitnessPoint(G, z.SecretWitness(testSecret("bob")))),
+		carol:     z.DLog("w", G, z.WitnessPoint(G, w)),
+		opening:   Relation(alice.Equations[0], z.Equation(commitment, z.Term("x", G), z.Term("r", H))),
+	}
+}
+
+// prove proves the statement and checks that the proof verifies
+func (f *sigmaFixture) prove(t *testing.T, statement zkx_models.SigmaStatement) *zkx_models.SigmaProof {
+	t.Helper()
+	proof, err := f.z.ProveStatement(statement, f.witnesses, "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.z.VerifyStatement(statement, *proof) {
+		t.Fatal("proof does not verify")
+	}
+	return proof
+}
+
+func TestSigmaRoundTrip(t *testing.T) {
+	f := newSigmaFixture(t)
+	for _, statement := range []zkx_models.SigmaStatement{
+		f.alice,
+		f.opening,
+		And(f.alice, f.carol),
+		Or(f.alice, f.bob),
+		Or(f.bob, f.alice),
+		Or(f.alice, f.opening),
+		Or(f.bob, And(f.opening, f.carol), Or(f.bob, f.bob)),
+		And(Or(f.bob, f.alice), f.carol),
+	} {
+		proof := f.prove(t, statement)
+		if f.z.VerifyStatement(f.bob, *proof) {
+			t.Fatal("proof verifies for another statement")
+		}
+		proof.Data = "other data"
+		if f.z.VerifyStatement(statement, *proof) {
+			t.Fatal("proof verifies over other data")
+		}
+	}
+	if _, err := f.z.ProveStatement(Or(f.bob, f.bob), f.witnesses, "data"); err == nil {
+		t.Fatal("proved an OR without a witness for any part")
+	}
+	if _, err := f.z.ProveStatement(f.bob, map[string]*zkx_types.Scalar{"y": f.witnesses["x"]}, "data"); err == nil {
+		t.Fatal("proved a relation with a wrong witness")
+	}
+}
+
+// Regression: parts proven together used to accept a shared witness name without linking the witnesses
+func TestSigmaRejectsWitnessSharedAcrossRelations(t *testing.T) {
+	f := newSigmaFixture(t)
+	for _, statement := range []zkx_models.SigmaStatement{
+		And(f.alice, f.opening),
+		And(Or(f.bob, f.alice), f.opening),
+		Or(f.bob, And(f.carol, And(f.alice, f.opening))),
+	} {
+		if _, err := f.z.ProveStatement(statement, f.witnesses, "data"); err == nil {
+			t.Fatal("proved a statement sharing a witness across relations")
+		}
+	}
+}
+
+// Regression: counts and values of the transcript used to have no fixed-width lengths, so shapes could collide
+func TestSigmaTranscriptIsPrefixFree(t *testing.T) {
+	f := newSigmaFixture(t)
+	G := f.z.marshalPoint(f.z.Generator())
+	term := func(witness string, base []byte) zkx_models.SigmaStatement {
+		return Relation(zkx_models.SigmaEquation{Public: G, Terms: []zkx_models.SigmaTerm{{Witness: witness, Base: base}}})
+	}
+	// The length of an empty name used to encode as nothing, so the name could move into the base
+	empty := term("", append([]byte{1, 'a'}, G...))
+	named := term("a", G)
+	if f.z.sigmaChallenge(empty, nil, "data").Cmp(f.z.sigmaChallenge(named, nil, "data")) == 0 {
+		t.Fatal("statements of different shape share a challenge")
+	}
+	if f.z.sigmaChallenge(f.alice, [][]byte{{1, 2}, {3}}, "").Cmp(f.z.sigmaChallenge(f.alice, [][]byte{{1}, {2, 3}}, "")) == 0 {
+		t.Fatal("commitments split differently share a challenge")
+	}
+}
+
+func TestSigmaRejectsTampering(t *testing.T) {
+	f := newSigmaFixture(t)
+	statement := Or(f.bob, f.alice)
+	proof := f.prove(t, statement)
+
+	// Moving challenge between the parts of an OR breaks the proof
+	forged := *proof
+	forged.Root.Children = append([]zkx_models.SigmaNode(nil), proof.Root.Children...)
+	forged.Root.Children[0].Challenge, forged.Root.Children[1].Challenge = proof.Root.Children[1].Challenge, proof.Root.Children[0].Challenge
+	if f.z.VerifyStatement(statement, forged) {
+		t.Fatal("proof verifies with swapped challenges")
+	}
+	forged.Root.Children = proof.Root.Children[:1]
+	if f.z.VerifyStatement(statement, forged) {
+		t.Fatal("proof verifies with a missing part")
+	}
+	response := append([]byte(nil), proof.Root.Children[1].Responses[0]...)
+	response[0] ^= 1
+	forged.Root.Children = []zkx_models.SigmaNode{proof.Root.Children[0], {Challenge: proof.Root.Children[1].Challenge, Responses: [][]byte{response}}}
+	if f.z.VerifyStatement(statement, forged) {
+		t.Fatal("proof verifies with a tampered response")
+	}
+}
+
+// Regression: every proof must have a single encoding, or one proof passes replay checks as many
+func TestSigmaRejectsNonCanonicalProofs(t *testing.T) {
+	f := newSigmaFixture(t)
+	N := f.z.Curve.Params().N
+	padded := func(b []byte) []byte { return append([]byte{0}, b...) }
+	shifted := func(b []byte) []byte { return new(big.Int).Add(new(big.Int).SetBytes(b), N).Bytes() }
+
+	statement := And(f.opening, Or(f.bob, f.carol))
+	proof := f.prove(t, statement)
+	mutations := map[string]func(proof *zkx_models.SigmaProof){
+		"padded root challenge":     func(p *zkx_models.SigmaProof) { p.Challenge = padded(p.Challenge) },
+		"root node challenge":       func(p *zkx_models.SigmaProof) { p.Root.Challenge = p.Challenge },
+		"AND part challenge":        func(p *zkx_models.SigmaProof) { p.Root.Children[0].Challenge = p.Challenge },
+		"relation in OR challenges": func(p *zkx_models.SigmaProof) { p.Root.Children[1].Challenge = p.Challenge },
+		"padded response": func(p *zkx_models.SigmaProof) {
+			p.Root.Children[0].Responses[0] = padded(p.Root.Children[0].Responses[0])
+		},
+		"response above the order": func(p *zkx_models.SigmaProof) {
+			p.Root.Children[0].Responses[1] = shifted(p.Root.Children[0].Responses[1])
+		},
+		"padded OR challenge": func(p *zkx_models.SigmaProof) {
+			p.Root.Children[1].Children[0].Challenge = padded(p.Root.Children[1].Children[0].Challenge)
+		},
+		"OR challenge above the order": func(p *zkx_models.SigmaProof) {
+			p.Root.Children[1].Children[1].Challenge = shifted(p.Root.Children[1].Children[1].Challenge)
+		},
+		"responses on an OR":     func(p *zkx_models.SigmaProof) { p.Root.Children[1].Responses = [][]byte{p.Challenge} },
+		"responses on an AND":    func(p *zkx_models.SigmaProof) { p.Root.Responses = [][]byte{p.Challenge} },
+		"parts under a relation": func(p *zkx_models.SigmaProof) { p.Root.Children[0].Children = []zkx_models.SigmaNode{{}} },
+	}
+	for name, mutate := range mutations {
+		forged := copySigmaProof(*proof)
+		mutate(&forged)
+		if f.z.VerifyStatement(statement, forged) {
+			t.Errorf("proof with a %s verifies", name)
+		}
+	}
+	if !f.z.VerifyStatement(statement, copySigmaProof(*proof)) {
+		t.Fatal("copied proof does not verify")
+	}
+}
+
+// copySigmaProof deep-copies a proof so that a mutation does not reach the original
+func copySigmaProof(proof zkx_models.SigmaProof) zkx_models.SigmaProof {
+	proof.Challenge = append([]byte(nil), proof.Challenge...)
+	proof.Root = copySigmaNode(proof.Root)
+	return proof
+}
+
+// copySigmaNode deep-copies a node of a proof
+func copySigmaNode(node zkx_models.SigmaNode) zkx_models.SigmaNode {
+	copied := zkx_models.SigmaNode{Challenge: append([]byte(nil), node.Challenge...)}
+	for _, response := range node.Responses {
+		copied.Responses = append(copied.Responses, append([]byte(nil), response...))
+	}
+	for _, child := range node.Children {
+		copied.Children = append(copied.Children, copySigmaNode(child))
+	}
+	return copied
+}
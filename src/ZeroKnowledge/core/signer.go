@@ -0,0 +1,33 @@
+package core
+
+import (
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+)
+
+// LoginSigner creates login data for one identity, curve-based or hash-based, that verifies with Login
+type LoginSigner interface {
+	Signature() zkx_models.ZeroKnowledgeSignature
+	Sign(data interface{}) (*zkx_models.ZeroKnowledgeData, error)
+}
+
+// CurveSigner signs with the curve identity of a secret
+type CurveSigner struct {
+	ZK     *ZeroKnowledge       // Instance the proofs are created with
+	Secret *zkx_types.SecretKey // Secret of the identity
+}
+
+// NewCurveSigner creates a signer for the curve identity of the secret
+func NewCurveSigner(z *ZeroKnowledge, secret *zkx_types.SecretKey) *CurveSigner {
+	return &CurveSigner{ZK: z, Secret: secret}
+}
+
+// Signature returns the signature of the secret
+func (s *CurveSigner) Signature() zkx_models.ZeroKnowledgeSignature {
+	return s.ZK.CreateSignature(s.Secret)
+}
+
+// Sign creates login data like Sign of ZeroKnowledge
+func (s *CurveSigner) Sign(data interface{}) (*zkx_models.ZeroKnowledgeData, error) {
+	return s.ZK.Sign(s.Secret, data), nil
+}
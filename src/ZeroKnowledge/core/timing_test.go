@@ -0,0 +1,78 @@
+//go:build timing
+
+package core
+
+import (
+	"crypto/rand"                           // Import cryptographic random number generator
+	"testing"                               // Import package for testing
+	"tmp/src/ZeroKnowledge/internal/timing" // Import the timing leakage test
+	zkx_types "tmp/src/ZeroKnowledge/types" // Import Zero Knowledge types
+)
+
+// The timing tests are slow and depend on a quiet machine, run them with go test -tags timing
+
+// timingSamples is the number of timed operations per measurement
+const timingSamples = 20000
+
+// timingAttempts is how often a measurement is repeated before a leak is reported, a single run can
+// exceed the threshold on a noisy machine but a real leak does so every time
+const timingAttempts = 3
+
+// checkTiming fails the test when every measurement exceeds timing.Threshold
+func checkTiming(t *testing.T, measure func(samples int) float64) {
+	var results []float64
+	for i := 0; i < timingAttempts; i++ {
+		leakage := measure(timingSamples)
+		t.Logf("t statistic %.2f", leakage)
+		if leakage <= timing.Threshold {
+			return
+		}
+		results = append(results, leakage)
+	}
+	t.Fatalf("t statistics %v exceed the threshold %v", results, timing.Threshold)
+}
+
+// proofTimingLeakage measures whether CreateProof takes longer for some secrets than for others.
+// Class 0 always proves with one fixed secret, class 1 with a fresh random one.
+func proofTimingLeakage(z *ZeroKnowledge) func(samples int) float64 {
+	fixed := zkx_types.NewSecretKey(make([]byte, 32))
+	return func(samples int) float64 {
+		return timing.Leakage(samples, func(class int) func() {
+			secret := fixed
+			if class == 1 {
+				random := make([]byte, 32)
+				rand.Read(random)
+				secret = zkx_types.NewSecretKey(random)
+			}
+			return func() {
+				z.CreateProof(secret, "Timing")
+			}
+		})
+	}
+}
+
+// scalarTimingLeakage runs the same test on a single Scalar multiplication, where the fixed class is zero
+func scalarTimingLeakage(z *ZeroKnowledge) func(samples int) float64 {
+	order := z.scalarOrder()
+	zero, _ := zkx_types.NewScalar(order, nil)
+	return func(samples int) float64 {
+		return timing.Leakage(samples, func(class int) func() {
+			x := zero
+			if class == 1 {
+				x, _ = zkx_types.RandomScalar(order, rand.Reader)
+			}
+			y, _ := zkx_types.RandomScalar(order, rand.Reader)
+			return func() {
+				new(zkx_types.Scalar).Mul(x, y)
+			}
+		})
+	}
+}
+
+func TestProofTimingLeakage(t *testing.T) {
+	checkTiming(t, proofTimingLeakage(newTestZK(t)))
+}
+
+func TestScalarTimingLeakage(t *testing.T) {
+	checkTiming(t, scalarTimingLeakage(newTestZK(t)))
+}
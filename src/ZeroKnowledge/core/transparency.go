@@ -0,0 +1,412 @@
+package core
+
+import (
+	"bytes"                                   // Import package for byte slice comparison
+	"encoding/json"                           // Import package for JSON encoding and decoding
+	"errors"                                  // Import package for error handling
+	"sort"                                    // Import package for sorting
+	"sync"                                    // Import package for synchronization primitives
+	"time"                                    // Import package for handling time
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"   // Import Zero Knowledge types
+	zkx_utils "tmp/src/ZeroKnowledge/utils"   // Import Zero Knowledge utility functions
+)
+
+// usersTreeCacheSize is the number of users trees a log keeps, heads are signed and audited at a few recent sizes
+const usersTreeCacheSize = 4
+
+// TransparencyLog is an append-only Merkle log of the signatures registered for each user
+type TransparencyLog struct {
+	mu      sync.Mutex            // Guards the fields below
+	entries []zkx_models.LogEntry // Logged registrations in order
+	leaves  [][]byte              // Leaf hashes of the entries
+	latest  map[string]int        // Index of the latest entry of each user
+	trees   map[int]*logUsersTree // Users trees of recent sizes, see usersTree
+}
+
+// logUsersTree holds the users of a tree in order and the leaf hashes of their latest entries
+type logUsersTree struct {
+	users  []string
+	leaves [][]byte
+}
+
+// NewTransparencyLog creates a new, empty TransparencyLog
+func NewTransparencyLog() *TransparencyLog {
+	return &TransparencyLog{latest: make(map[string]int), trees: make(map[int]*logUsersTree)}
+}
+
+// Append logs a registration and returns its index
+func (l *TransparencyLog) Append(user string, signature zkx_models.ZeroKnowledgeSignature) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	previous, ok := l.latest[user]
+	if !ok {
+		previous = -1
+	}
+	entry := zkx_models.LogEntry{
+		User:      user,
+		Signature: signature,
+		Timestamp: time.Now().UTC(),
+		Previous:  previous,
+	}
+	l.entries = append(l.entries, entry)
+	l.leaves = append(l.leaves, zkx_utils.MerkleLeafHash(logLeaf(entry)))
+	l.latest[user] = len(l.entries) - 1
+	return len(l.entries) - 1
+}
+
+// Size returns the number of logged entries
+func (l *TransparencyLog) Size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries)
+}
+
+// Entry returns the entry at index
+func (l *TransparencyLog) Entry(index int) (zkx_models.LogEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if index < 0 || index >= len(l.entries) {
+		return zkx_models.LogEntry{}, false
+	}
+	return l.entries[index], true
+}
+
+// Latest returns the index of the latest entry of the user
+func (l *TransparencyLog) Latest(user string) (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	index, ok := l.latest[user]
+	return index, ok
+}
+
+// Root returns the Merkle root of the first size entries
+func (l *TransparencyLog) Root(size int) ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if size < 0 || size > len(l.leaves) {
+		return nil, errors.New("Tree size out of range")
+	}
+	return zkx_utils.MerkleRoot(l.leaves[:size]), nil
+}
+
+// History returns the indices of the entries of the user in the tree of the given size, oldest first,
+// starting at the entry accepted or at the first entry of the user if accepted is -1
+func (l *TransparencyLog) History(user string, size int, accepted int) ([]int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if size < 0 || size > len(l.entries) {
+		return nil, errors.New("Tree size out of range")
+	}
+	index, ok := l.latest[user]
+	for ok && index >= size {
+		index = l.entries[index].Previous
+	}
+	var history []int
+	for index >= 0 && index >= accepted {
+		history = append([]int{index}, history...)
+		index = l.entries[index].Previous
+	}
+	if len(history) == 0 || (accepted >= 0 && history[0] != accepted) {
+		return nil, errors.New("User has no such entry in the log")
+	}
+	return history, nil
+}
+
+// Users returns the number of users and the root of the users tree in the tree of the given size
+func (l *TransparencyLog) Users(size int) (int, []byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if size < 0 || size > len(l.entries) {
+		return 0, nil, errors.New("Tree size out of range")
+	}
+	_, leaves := l.usersTree(size)
+	return len(leaves), zkx_utils.MerkleRoot(leaves), nil
+}
+
+// LatestProof proves which entry is the latest of the user in the tree of the given size
+func (l *TransparencyLog) LatestProof(user string, size int) (*zkx_models.InclusionProof, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if size < 0 || size > len(l.entries) {
+		return nil, errors.New("Tree size out of range")
+	}
+	users, leaves := l.usersTree(size)
+	position := sort.SearchStrings(users, user)
+	if position == len(users) || users[position] != user {
+		return nil, errors.New("User is not in the log")
+	}
+	return &zkx_models.InclusionProof{
+		Index: position,
+		Size:  len(leaves),
+		Path:  zkx_utils.MerkleInclusionPath(leaves, position),
+	}, nil
+}
+
+// usersTree returns the users of the first size entries in order and the leaf hashes of their latest
+// entries, callers must hold the lock. The log never changes below its size, so trees are cached by size.
+func (l *TransparencyLog) usersTree(size int) ([]string, [][]byte) {
+	if tree, ok := l.trees[size]; ok {
+		return tree.users, tree.leaves
+	}
+	latest := make(map[string]int)
+	for index, entry := range l.entries[:size] {
+		latest[entry.User] = index
+	}
+	users := make([]string, 0, len(latest))
+	for user := range latest {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+	leaves := make([][]byte, len(users))
+	for i, user := range users {
+		leaves[i] = zkx_utils.MerkleLeafHash(userLeaf(user, latest[user]))
+	}
+
+	// Make room by forgetting the smallest tree, older heads are audited the least
+	if len(l.trees) >= usersTreeCacheSize {
+		smallest := size
+		for cached := range l.trees {
+			if cached < smallest {
+				smallest = cached
+			}
+		}
+		if smallest == size {
+			return users, leaves
+		}
+		delete(l.trees, smallest)
+	}
+	l.trees[size] = &logUsersTree{users: users, leaves: leaves}
+	return users, leaves
+}
+
+// InclusionProof proves that the entry at index is part of the tree of the given size
+func (l *TransparencyLog) InclusionProof(index int, size int) (*zkx_models.InclusionProof, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if size > len(l.leaves) || index < 0 || index >= size {
+		return nil, errors.New("Tree size out of range")
+	}
+	return &zkx_models.InclusionProof{
+		Index: index,
+		Size:  size,
+		Path:  zkx_utils.MerkleInclusionPath(l.leaves[:size], index),
+	}, nil
+}
+
+// ConsistencyProof proves that the tree of oldSize entries is a prefix of the tree of newSize entries
+func (l *TransparencyLog) ConsistencyProof(oldSize int, newSize int) (*zkx_models.ConsistencyProof, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if oldSize < 0 || oldSize > newSize || newSize > len(l.leaves) {
+		return nil, errors.New("Tree size out of range")
+	}
+	return &zkx_models.ConsistencyProof{
+		OldSize: oldSize,
+		NewSize: newSize,
+		Path:    zkx_utils.MerkleConsistencyPath(l.leaves[:newSize], oldSize),
+	}, nil
+}
+
+// LoggedRegistry wraps a SignatureRegistry and logs every registration and rotation
+type LoggedRegistry struct {
+	SignatureRegistry                  // Registry the calls are passed on to
+	Log               *TransparencyLog // Log the registrations are appended to
+	mu                sync.Mutex       // Keeps the log in the order the registry changed
+}
+
+// Register stores the first signature of a user and logs it
+func (r *LoggedRegistry) Register(user string, signature zkx_models.ZeroKnowledgeSignature) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.SignatureRegistry.Register(user, signature); err != nil {
+		return err
+	}
+	r.Log.Append(user, signature)
+	return nil
+}
+
+// Swap replaces the signature of a user and logs the new one
+func (r *LoggedRegistry) Swap(event zkx_models.RotationEvent, nonce []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.SignatureRegistry.Swap(event, nonce); err != nil {
+		return err
+	}
+	r.Log.Append(event.User, event.New)
+	return nil
+}
+
+// SignTreeHead signs the current root of the log with the secret of the log
+func (z *ZeroKnowledge) SignTreeHead(secret *zkx_types.SecretKey, log *TransparencyLog) (*zkx_models.SignedTreeHead, error) {
+	size := log.Size()
+	root, err := log.Root(size)
+	if err != nil {
+		return nil, err
+	}
+	users, usersRoot, err := log.Users(size)
+	if err != nil {
+		return nil, err
+	}
+	head := &zkx_models.SignedTreeHead{
+		Size:      size,
+		Root:      root,
+		Users:     users,
+		UsersRoot: usersRoot,
+		Timestamp: time.Now().UTC(),
+		Log:       z.CreateSignature(secret),
+	}
+	head.Proof = z.CreateProof(secret, treeHeadTranscript(head))
+	return head, nil
+}
+
+// VerifyTreeHead checks that the head was signed by the pinned log
+func (z *ZeroKnowledge) VerifyTreeHead(head zkx_models.SignedTreeHead, pinned zkx_models.ZeroKnowledgeSignature) bool {
+	if !bytes.Equal(head.Log.Signature, pinned.Signature) {
+		return false
+	}
+	return z.Verify(head.Proof, pinned, treeHeadTranscript(&head))
+}
+
+// NewLogAudit collects what a user needs to audit its entries against the head it saw last and the
+// entry it accepted last, -1 before the first audit
+func (z *ZeroKnowledge) NewLogAudit(head zkx_models.SignedTreeHead, log *TransparencyLog, user string, seenSize int, accepted int) (*zkx_models.LogAudit, error) {
+	history, err := log.History(user, head.Size, accepted)
+	if err != nil {
+		return nil, err
+	}
+	audit := &zkx_models.LogAudit{Head: head}
+	for _, index := range history {
+		entry, _ := log.Entry(index)
+		inclusion, err := log.InclusionProof(index, head.Size)
+		if err != nil {
+			return nil, err
+		}
+		audit.Entries = append(audit.Entries, entry)
+		audit.Inclusions = append(audit.Inclusions, *inclusion)
+	}
+	latest, err := log.LatestProof(user, head.Size)
+	if err != nil {
+		return nil, err
+	}
+	consistency, err := log.ConsistencyProof(seenSize, head.Size)
+	if err != nil {
+		return nil, err
+	}
+	audit.Latest = *latest
+	audit.Consistency = *consistency
+	return audit, nil
+}
+
+// LogAuditor is kept by a client to check on each login that the log still holds its own signature
+type LogAuditor struct {
+	User      string                            // User the client logs in as
+	Signature zkx_models.ZeroKnowledgeSignature // Signature the client registered
+	Log       zkx_models.ZeroKnowledgeSignature // Pinned signature of the log
+	Head      *zkx_models.SignedTreeHead        // Latest verified head, nil before the first audit
+	Index     int                               // Index of the latest entry accepted, -1 before the first audit
+}
+
+// NewLogAuditor creates an auditor for the user, its signature and the pinned log
+func NewLogAuditor(user string, signature zkx_models.ZeroKnowledgeSignature, log zkx_models.ZeroKnowledgeSignature) *LogAuditor {
+	return &LogAuditor{User: user, Signature: signature, Log: log, Index: -1}
+}
+
+// SeenSize returns the size of the latest verified head, which the server needs for the consistency proof
+func (a *LogAuditor) SeenSize() int {
+	if a.Head == nil {
+		return 0
+	}
+	return a.Head.Size
+}
+
+// Audit checks the head, its consistency with the last one seen and every entry of the user since the one
+// accepted last, and then remembers the head and the latest entry. The entries must be linked through
+// Previous and the last one must be the latest of the user in the users tree, so the log cannot show an
+// older entry or leave one out without signing a head that monitors can prove wrong. Every entry after
+// the one accepted last must hold our signature, a rotation we did not make shows up even if reverted.
+func (z *ZeroKnowledge) Audit(auditor *LogAuditor, audit zkx_models.LogAudit) error {
+	head := audit.Head
+	if !z.VerifyTreeHead(head, auditor.Log) {
+		return zkx_errors.ErrTreeHead
+	}
+
+	// The new head must extend the one we saw last
+	consistency := audit.Consistency
+	oldSize, oldRoot := 0, []byte(nil)
+	if auditor.Head != nil {
+		oldSize, oldRoot = auditor.Head.Size, auditor.Head.Root
+	}
+	if consistency.OldSize != oldSize || consistency.NewSize != head.Size {
+		return zkx_errors.ErrLogInconsistent
+	}
+	if !zkx_utils.VerifyMerkleConsistency(oldSize, head.Size, oldRoot, head.Root, consistency.Path) {
+		return zkx_errors.ErrLogInconsistent
+	}
+
+	// Our entries must be in the tree and chained from the one we accepted last
+	if len(audit.Entries) == 0 || len(audit.Entries) != len(audit.Inclusions) {
+		return zkx_errors.ErrNotIncluded
+	}
+	previous := auditor.Index
+	for i, entry := range audit.Entries {
+		inclusion := audit.Inclusions[i]
+		leaf := zkx_utils.MerkleLeafHash(logLeaf(entry))
+		if inclusion.Size != head.Size || !zkx_utils.VerifyMerkleInclusion(leaf, inclusion.Index, head.Size, inclusion.Path, head.Root) {
+			return zkx_errors.ErrNotIncluded
+		}
+		if entry.User != auditor.User {
+			return zkx_errors.ErrEntryMismatch
+		}
+		switch {
+		case i == 0 && auditor.Index >= 0:
+			if inclusion.Index != auditor.Index {
+				return zkx_errors.ErrLogInconsistent
+			}
+			continue // Accepted before, it may hold a signature we have since rotated away from
+		case i == 0:
+			if entry.Previous != -1 {
+				return zkx_errors.ErrLogInconsistent
+			}
+		default:
+			if entry.Previous != previous || inclusion.Index <= previous {
+				return zkx_errors.ErrLogInconsistent
+			}
+		}
+		previous = inclusion.Index
+
+		// Before the first audit the history may hold signatures we rotated away from ourselves
+		if (auditor.Index >= 0 || i == len(audit.Entries)-1) && !sameSignature(entry.Signature, auditor.Signature) {
+			return zkx_errors.ErrEntryMismatch
+		}
+	}
+
+	// The last entry must be the latest one of the user
+	latest := zkx_utils.MerkleLeafHash(userLeaf(auditor.User, previous))
+	if audit.Latest.Size != head.Users || !zkx_utils.VerifyMerkleInclusion(latest, audit.Latest.Index, head.Users, audit.Latest.Path, head.UsersRoot) {
+		return zkx_errors.ErrNotIncluded
+	}
+
+	auditor.Head = &head
+	auditor.Index = previous
+	return nil
+}
+
+// logLeaf encodes an entry as a leaf of the log
+func logLeaf(entry zkx_models.LogEntry) []byte {
+	leaf, _ := json.Marshal([]interface{}{"TransparencyLog/Entry", entry.User, entry.Signature.Signature, entry.Signature.Service, entry.Timestamp.UnixNano(), entry.Previous})
+	return leaf
+}
+
+// userLeaf encodes the latest entry of a user as a leaf of the users tree
+func userLeaf(user string, index int) []byte {
+	leaf, _ := json.Marshal([]interface{}{"TransparencyLog/User", user, index})
+	return leaf
+}
+
+// treeHeadTranscript encodes what the log signs for a tree head
+func treeHeadTranscript(head *zkx_models.SignedTreeHead) string {
+	transcript, _ := json.Marshal([]interface{}{"TransparencyLog/TreeHead", head.Size, head.Root, head.Users, head.UsersRoot, head.Timestamp.UnixNano()})
+	return string(transcript)
+}
@@ -0,0 +1,212 @@
+package core
+
+import (
+	"bytes"                                   // Import package for byte slice comparison
+	"errors"                                  // Import package for error handling
+	"fmt"                                     // Import package for formatted I/O
+	"sync"                                    // Import package for synchronization primitives
+	"testing"                                 // Import package for testing
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+// testLog is a transparency log with the pinned signature of its key
+type testLog struct {
+	z   *ZeroKnowledge
+	log *TransparencyLog
+	key zkx_models.ZeroKnowledgeSignature
+}
+
+// head signs the current state of the log
+func (l *testLog) head(t *testing.T) zkx_models.SignedTreeHead {
+	t.Helper()
+	head, err := l.z.SignTreeHead(testSecret("log"), l.log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *head
+}
+
+// audit builds the audit the server hands the auditor
+func (l *testLog) audit(t *testing.T, auditor *LogAuditor) zkx_models.LogAudit {
+	t.Helper()
+	audit, err := l.z.NewLogAudit(l.head(t), l.log, auditor.User, auditor.SeenSize(), auditor.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *audit
+}
+
+// newTestLog creates a log where alice registered, followed by bob
+func newTestLog(t *testing.T) (*testLog, *LogAuditor) {
+	z := newTestZK(t)
+	l := &testLog{z: z, log: NewTransparencyLog(), key: z.CreateSignature(testSecret("log"))}
+	alice := z.CreateSignature(testSecret("alice"))
+	l.log.Append("alice", alice)
+	l.log.Append("bob", z.CreateSignature(testSecret("bob")))
+	return l, NewLogAuditor("alice", alice, l.key)
+}
+
+func TestAuditRoundTrip(t *testing.T) {
+	l, auditor := newTestLog(t)
+	if err := l.z.Audit(auditor, l.audit(t, auditor)); err != nil {
+		t.Fatal(err)
+	}
+	if auditor.Index != 0 || auditor.SeenSize() != 2 {
+		t.Fatalf("auditor remembered entry %d at size %d", auditor.Index, auditor.SeenSize())
+	}
+	l.log.Append("bob", l.z.CreateSignature(testSecret("bob 2")))
+	l.log.Append("carol", l.z.CreateSignature(testSecret("carol")))
+	if err := l.z.Audit(auditor, l.audit(t, auditor)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A rotation we make ourselves passes once the auditor knows the new signature
+	rotated := l.z.CreateSignature(testSecret("alice 2"))
+	l.log.Append("alice", rotated)
+	auditor.Signature = rotated
+	if err := l.z.Audit(auditor, l.audit(t, auditor)); err != nil {
+		t.Fatal(err)
+	}
+	if auditor.Index != 4 {
+		t.Fatalf("auditor remembered entry %d", auditor.Index)
+	}
+}
+
+func TestAuditDetectsRotation(t *testing.T) {
+	l, auditor := newTestLog(t)
+	if err := l.z.Audit(auditor, l.audit(t, auditor)); err != nil {
+		t.Fatal(err)
+	}
+	l.log.Append("alice", l.z.CreateSignature(testSecret("mallory")))
+	if err := l.z.Audit(auditor, l.audit(t, auditor)); !errors.Is(err, zkx_errors.ErrEntryMismatch) {
+		t.Fatalf("rotation to another key gave %v", err)
+	}
+
+	// Rotating back does not hide the entry in between
+	l.log.Append("alice", auditor.Signature)
+	if err := l.z.Audit(auditor, l.audit(t, auditor)); !errors.Is(err, zkx_errors.ErrEntryMismatch) {
+		t.Fatalf("reverted rotation gave %v", err)
+	}
+}
+
+// Regression: the server used to get away with showing an older entry of the user
+func TestAuditRejectsStaleEntry(t *testing.T) {
+	l, auditor := newTestLog(t)
+	if err := l.z.Audit(auditor, l.audit(t, auditor)); err != nil {
+		t.Fatal(err)
+	}
+	stale := l.audit(t, auditor)
+	l.log.Append("alice", l.z.CreateSignature(testSecret("mallory")))
+
+	// The old entry with its proofs against the new head, and the users proof of the old head
+	audit := l.audit(t, auditor)
+	audit.Entries = audit.Entries[:1]
+	audit.Inclusions = audit.Inclusions[:1]
+	audit.Latest = stale.Latest
+	if err := l.z.Audit(auditor, audit); !errors.Is(err, zkx_errors.ErrNotIncluded) {
+		t.Fatalf("stale entry gave %v", err)
+	}
+
+	// Leaving out the rotation in the middle of the history breaks the chain
+	l.log.Append("alice", auditor.Signature)
+	audit = l.audit(t, auditor)
+	audit.Entries = append(audit.Entries[:1:1], audit.Entries[2])
+	audit.Inclusions = append(audit.Inclusions[:1:1], audit.Inclusions[2])
+	if err := l.z.Audit(auditor, audit); !errors.Is(err, zkx_errors.ErrLogInconsistent) {
+		t.Fatalf("history with a gap gave %v", err)
+	}
+	if auditor.Index != 0 {
+		t.Fatal("auditor moved on after a failed audit")
+	}
+}
+
+func TestAuditRejectsForgedHeads(t *testing.T) {
+	l, auditor := newTestLog(t)
+	audit := l.audit(t, auditor)
+	audit.Head.Size++
+	if err := l.z.Audit(auditor, audit); !errors.Is(err, zkx_errors.ErrTreeHead) {
+		t.Fatalf("tampered head gave %v", err)
+	}
+	if err := l.z.Audit(auditor, l.audit(t, auditor)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A log that rewrites history cannot prove consistency with the head we saw
+	seen := *auditor.Head
+	rewritten := &testLog{z: l.z, log: NewTransparencyLog(), key: l.key}
+	rewritten.log.Append("alice", auditor.Signature)
+	rewritten.log.Append("mallory", l.z.CreateSignature(testSecret("mallory")))
+	rewritten.log.Append("bob", l.z.CreateSignature(testSecret("bob")))
+	forged, err := l.z.NewLogAudit(rewritten.head(t), rewritten.log, "alice", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged.Consistency.OldSize = seen.Size
+	if err := l.z.Audit(auditor, *forged); !errors.Is(err, zkx_errors.ErrLogInconsistent) {
+		t.Fatalf("rewritten log gave %v", err)
+	}
+}
+
+// orderedRegistry records the order in which registrations reach the registry
+type orderedRegistry struct {
+	*MemoryRegistry
+	mu    sync.Mutex
+	users []string
+}
+
+// Register records the user and passes the registration on
+func (r *orderedRegistry) Register(user string, signature zkx_models.ZeroKnowledgeSignature) error {
+	r.mu.Lock()
+	r.users = append(r.users, user)
+	r.mu.Unlock()
+	return r.MemoryRegistry.Register(user, signature)
+}
+
+// Regression: concurrent registrations must be logged in the order the registry applied them
+func TestLoggedRegistryKeepsOrder(t *testing.T) {
+	z := newTestZK(t)
+	inner := &orderedRegistry{MemoryRegistry: NewMemoryRegistry()}
+	registry := &LoggedRegistry{SignatureRegistry: inner, Log: NewTransparencyLog()}
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(user string) {
+			defer wg.Done()
+			if err := registry.Register(user, z.CreateSignature(testSecret(user))); err != nil {
+				t.Error(err)
+			}
+		}(fmt.Sprint("user ", i))
+	}
+	wg.Wait()
+	for i, user := range inner.users {
+		entry, ok := registry.Log.Entry(i)
+		if !ok || entry.User != user {
+			t.Fatalf("log entry %d is not the registration of %s", i, user)
+		}
+	}
+}
+
+func TestUsersTreeOfOlderSize(t *testing.T) {
+	l, _ := newTestLog(t)
+	users, root, err := l.log.Users(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2*usersTreeCacheSize; i++ {
+		l.log.Append(fmt.Sprint("user ", i), l.z.CreateSignature(testSecret("user")))
+		if _, _, err := l.log.Users(l.log.Size()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	again, rootAgain, err := l.log.Users(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if users != 2 || again != users || !bytes.Equal(root, rootAgain) {
+		t.Fatal("users tree of an older size changed")
+	}
+	if _, err := l.log.LatestProof("user 0", 2); err == nil {
+		t.Fatal("user appended later is in an older users tree")
+	}
+}
@@ -0,0 +1,415 @@
+package core
+
+import (
+	"bytes"                                           // Import package for byte slice comparison
+	"crypto/hmac"                                     // Import package for HMAC construction
+	"crypto/rand"                                     // Import cryptographic random number generator
+	"encoding/binary"                                 // Import package for big-endian integers
+	"encoding/hex"                                    // Import package for hexadecimal encoding
+	"errors"                                          // Import package for error handling
+	"fmt"                                             // Import package for formatted I/O
+	"os"                                              // Import package for the state file
+	"path/filepath"                                   // Import package for file paths
+	"strconv"                                         // Import package for parsing the state counter
+	"strings"                                         // Import package for string manipulation
+	"sync"                                            // Import package for synchronization primitives
+	zkx_algorithms "tmp/src/ZeroKnowledge/algorithms" // Import Zero Knowledge hash algorithms
+	zkx_errors "tmp/src/ZeroKnowledge/errors"         // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models"         // Import Zero Knowledge models
+	zkx_types "tmp/src/ZeroKnowledge/types"           // Import Zero Knowledge types
+)
+
+// Hash-based identities are XMSS trees of WOTS+ one-time keys with w = 16. Every hash is keyed with
+// the public seed and the address of the value it computes, as in the simple tweakable hashes of
+// SPHINCS+, so the scheme only relies on the hash function and survives quantum attacks on discrete
+// log. A leaf must never sign twice, which is why the signer reserves its index in a HashKeyState
+// before any signature leaves it.
+
+// Sizes of the WOTS+ one-time signatures
+const (
+	hashNodeSize = 32                     // Size of every hash value
+	wotsW        = 16                     // Winternitz parameter, each chain has wotsW-1 steps
+	wotsLen1     = 2 * hashNodeSize       // Base-w digits of the message digest
+	wotsLen2     = 3                      // Base-w digits of the checksum
+	wotsLen      = wotsLen1 + wotsLen2    // Chains of a one-time key
+	wotsMaxSum   = wotsLen1 * (wotsW - 1) // Largest checksum
+)
+
+// Domain tags of the keyed hashes
+const (
+	xmssTagSecret  byte = iota // Secret start of a chain
+	xmssTagChain               // Step of a chain
+	xmssTagLeaf                // Compression of a one-time public key into a leaf
+	xmssTagNode                // Inner node of the tree
+	xmssTagMessage             // Digest of the signed data
+	xmssTagRandom              // Randomness of a signature
+)
+
+// hashScheme describes a parameter set of hash-based identities
+type hashScheme struct {
+	hash   string // Hash algorithm of ZeroKnowledge/algorithms, its digests must be hashNodeSize bytes
+	height int    // Height of the tree, a key signs 2^height times
+}
+
+// hashSchemes lists the supported parameter sets, named after RFC 8391 although the encoding differs
+var hashSchemes = map[string]hashScheme{
+	"XMSS-SHA2_10_256": {hash: "sha256", height: 10},
+	"XMSS-SHA2_16_256": {hash: "sha256", height: 16},
+	"XMSS-SHA3_10_256": {hash: "sha3_256", height: 10},
+	"XMSS-SHA3_16_256": {hash: "sha3_256", height: 16},
+}
+
+// IsHashSignature reports whether a signature is a hash-based identity
+func IsHashSignature(signature zkx_models.ZeroKnowledgeSignature) bool {
+	_, ok := hashSchemes[signature.Params.Algorithm]
+	return ok
+}
+
+// GenerateHashKey generates a new hash-based identity from random seeds, keys of height 16 take a while
+// to build. The seeds are never derived from a secret, so the key cannot be built again by accident: it and
+// its state must always travel together, a key copied or restored from a backup without its state starts
+// over at used leaves and signs with one-time keys a second time.
+func (z *ZeroKnowledge) GenerateHashKey(algorithm string) (*zkx_models.HashKey, error) {
+	scheme, ok := hashSchemes[algorithm]
+	if !ok {
+		return nil, errors.New("Unsupported hash-based scheme")
+	}
+	secretSeed := make([]byte, hashNodeSize)
+	defer wipe(secretSeed)
+	publicSeed := make([]byte, hashNodeSize)
+	if _, err := rand.Read(secretSeed); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(publicSeed); err != nil {
+		return nil, err
+	}
+
+	key := &zkx_models.HashKey{
+		Params: zkx_models.ZeroKnowledgeParams{
+			Algorithm: algorithm,
+			Salt:      publicSeed,
+		},
+		Seed: zkx_types.NewSecretKey(secretSeed),
+	}
+	tree := scheme.tree(key)
+	key.Root = tree[scheme.height][0]
+	return key, nil
+}
+
+// HashKeyState hands out the leaves of a hash-based key, it must never return the same index twice
+type HashKeyState interface {
+	Reserve() (uint64, error)
+}
+
+// MemoryHashKeyState counts used leaves in memory, it is only safe for keys that never outlive the process
+type MemoryHashKeyState struct {
+	mu   sync.Mutex // Guards next
+	next uint64     // Next unused leaf
+}
+
+// NewMemoryHashKeyState creates a state starting at the first leaf
+func NewMemoryHashKeyState() *MemoryHashKeyState {
+	return &MemoryHashKeyState{}
+}
+
+// Reserve returns the next unused leaf
+func (s *MemoryHashKeyState) Reserve() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index := s.next
+	s.next++
+	return index, nil
+}
+
+// FileHashKeyState keeps the root of its key and the next unused leaf in a file, and refuses to hand out
+// leaves for any other key. The counter is synced to disk before an index is handed out, so a crash skips
+// leaves instead of reusing them, and every reservation holds an exclusive lock on Path + ".lock", so
+// processes sharing the file never hand out the same leaf.
+type FileHashKeyState struct {
+	Path string     // File holding the root and the next unused leaf
+	Root []byte     // Root of the key the leaves belong to
+	mu   sync.Mutex // Serializes reservations of this process
+}
+
+// NewFileHashKeyState opens the state file of the key with the root, creating it at the first leaf if it does
+// not exist
+func NewFileHashKeyState(path string, root []byte) (*FileHashKeyState, error) {
+	if len(root) == 0 {
+		return nil, errors.New("Hash key state needs the root of its key")
+	}
+	state := &FileHashKeyState{Path: path, Root: append([]byte(nil), root...)}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err == nil {
+		_, err = file.WriteString(state.content(0))
+		if err == nil {
+			err = file.Sync()
+		}
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	} else if errors.Is(err, os.ErrExist) {
+		_, err = state.read()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Reserve returns the next unused leaf after storing its successor
+func (s *FileHashKeyState) Reserve() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The counter file is replaced on every reservation, so the lock lives in a file of its own
+	lock, err := lockFile(s.Path + ".lock")
+	if err != nil {
+		return 0, err
+	}
+	defer unlockFile(lock)
+	index, err := s.read()
+	if err != nil {
+		return 0, err
+	}
+
+	// Write the successor to a temporary file and rename it, so the counter is never half written
+	temporary := s.Path + ".tmp"
+	file, err := os.OpenFile(temporary, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	_, err = file.WriteString(s.content(index + 1))
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temporary, s.Path)
+	}
+	if err == nil {
+		err = syncDir(filepath.Dir(s.Path))
+	}
+	if err != nil {
+		return 0, err
+	}
+	return index, nil
+}
+
+// read returns the next unused leaf stored in the file, after checking that the file belongs to the key
+func (s *FileHashKeyState) read() (uint64, error) {
+	content, err := os.ReadFile(s.Path)
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) != 2 {
+		return 0, errors.New("Corrupt hash key state")
+	}
+	root, err := hex.DecodeString(fields[0])
+	if err != nil {
+		return 0, errors.New("Corrupt hash key state")
+	}
+	if !bytes.Equal(root, s.Root) {
+		return 0, errors.New("Hash key state belongs to another key")
+	}
+	index, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, errors.New("Corrupt hash key state")
+	}
+	return index, nil
+}
+
+// content encodes the root and the next unused leaf as they are stored in the file
+func (s *FileHashKeyState) content(next uint64) string {
+	return hex.EncodeToString(s.Root) + "\n" + strconv.FormatUint(next, 10) + "\n"
+}
+
+// syncDir flushes a directory, making a rename within it durable
+func syncDir(dir string) error {
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	err = file.Sync()
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// HashSigner signs login data with a hash-based key, consuming one leaf per signature
+type HashSigner struct {
+	Key    *zkx_models.HashKey // Key the signatures are made with
+	State  HashKeyState        // Persistent counter of used leaves
+	scheme hashScheme          // Parameter set of the key
+	tree   [][][]byte          // Nodes of every level of the tree, leaves first
+}
+
+// NewHashSigner builds the tree of the key, which is needed for the authentication paths
+func NewHashSigner(key *zkx_models.HashKey, state HashKeyState) (*HashSigner, error) {
+	scheme, ok := hashSchemes[key.Params.Algorithm]
+	if !ok {
+		return nil, errors.New("Unsupported hash-based scheme")
+	}
+	tree := scheme.tree(key)
+	if !bytes.Equal(tree[scheme.height][0], key.Root) {
+		return nil, errors.New("Hash key root does not match its seed")
+	}
+	return &HashSigner{Key: key, State: state, scheme: scheme, tree: tree}, nil
+}
+
+// Signature returns the public identity of the key, it is registered and issued in JWTs like a curve signature
+func (s *HashSigner) Signature() zkx_models.ZeroKnowledgeSignature {
+	return zkx_models.ZeroKnowledgeSignature{
+		Params:    s.Key.Params,
+		Signature: s.Key.Root,
+	}
+}
+
+// Sign works like Sign of ZeroKnowledge, the proof verifies with Verify and Login against Signature
+func (s *HashSigner) Sign(data interface{}) (*zkx_models.ZeroKnowledgeData, error) {
+	reserved, err := s.State.Reserve()
+	if err != nil {
+		return nil, err
+	}
+	if reserved >= uint64(1)<<s.scheme.height {
+		return nil, zkx_errors.ErrHashKeyExhausted
+	}
+	index := uint32(reserved)
+	payload := fmt.Sprint(data)
+	digest := hashValues(payload)
+	publicSeed := s.Key.Params.Salt
+
+	random := s.scheme.secret(s.Key.Seed, xmssTagRandom, index, 0, digest[:])
+	digits := wotsDigits(s.scheme.hashTo(publicSeed, xmssTagMessage, index, 0, 0, random, s.Key.Root, digest[:]))
+	chains := make([][]byte, wotsLen)
+	for i, digit := range digits {
+		start := s.scheme.secret(s.Key.Seed, xmssTagSecret, index, uint32(i), nil)
+		chains[i] = s.scheme.chain(publicSeed, index, uint32(i), start, 0, digit)
+		wipe(start)
+	}
+	path := make([][]byte, s.scheme.height)
+	for level := range path {
+		path[level] = s.tree[level][(index>>level)^1]
+	}
+
+	return &zkx_models.ZeroKnowledgeData{
+		Data: payload,
+		Proof: zkx_models.ZeroKnowledgeProof{
+			Params: s.Key.Params,
+			Hash: &zkx_models.HashProof{
+				Index:  index,
+				Random: random,
+				Chains: chains,
+				Path:   path,
+			},
+		},
+	}, nil
+}
+
+// verifyHashProof checks a hash-based proof over the data against the root of the signature
+func verifyHashProof(proof zkx_models.ZeroKnowledgeProof, signature zkx_models.ZeroKnowledgeSignature, data interface{}) bool {
+	scheme, ok := hashSchemes[signature.Params.Algorithm]
+	signed := proof.Hash
+	if !ok || signed == nil || uint64(signed.Index) >= uint64(1)<<scheme.height {
+		return false
+	}
+	if len(signed.Random) != hashNodeSize || len(signed.Chains) != wotsLen || len(signed.Path) != scheme.height {
+		return false
+	}
+	for _, value := range append(append([][]byte(nil), signed.Chains...), signed.Path...) {
+		if len(value) != hashNodeSize {
+			return false
+		}
+	}
+	digest := hashValues(data)
+	publicSeed := signature.Params.Salt
+
+	// Complete every chain to recover the one-time public key
+	digits := wotsDigits(scheme.hashTo(publicSeed, xmssTagMessage, signed.Index, 0, 0, signed.Random, signature.Signature, digest[:]))
+	public := make([][]byte, wotsLen)
+	for i, digit := range digits {
+		public[i] = scheme.chain(publicSeed, signed.Index, uint32(i), signed.Chains[i], digit, wotsW-1-digit)
+	}
+
+	// Climb from the leaf to the root
+	node := scheme.hashTo(publicSeed, xmssTagLeaf, signed.Index, 0, 0, public...)
+	position := signed.Index
+	for level, sibling := range signed.Path {
+		if position&1 == 0 {
+			node = scheme.hashTo(publicSeed, xmssTagNode, uint32(level+1), position>>1, 0, node, sibling)
+		} else {
+			node = scheme.hashTo(publicSeed, xmssTagNode, uint32(level+1), position>>1, 0, sibling, node)
+		}
+		position >>= 1
+	}
+	return bytes.Equal(node, signature.Signature)
+}
+
+// tree computes every node of the tree of a key, level 0 holds the leaves
+func (s hashScheme) tree(key *zkx_models.HashKey) [][][]byte {
+	publicSeed := key.Params.Salt
+	leaves := make([][]byte, 1<<s.height)
+	public := make([][]byte, wotsLen)
+	for leaf := range leaves {
+		for i := range public {
+			start := s.secret(key.Seed, xmssTagSecret, uint32(leaf), uint32(i), nil)
+			public[i] = s.chain(publicSeed, uint32(leaf), uint32(i), start, 0, wotsW-1)
+			wipe(start)
+		}
+		leaves[leaf] = s.hashTo(publicSeed, xmssTagLeaf, uint32(leaf), 0, 0, public...)
+	}
+	tree := [][][]byte{leaves}
+	for level := 1; level <= s.height; level++ {
+		below := tree[level-1]
+		nodes := make([][]byte, len(below)/2)
+		for j := range nodes {
+			nodes[j] = s.hashTo(publicSeed, xmssTagNode, uint32(level), uint32(j), 0, below[2*j], below[2*j+1])
+		}
+		tree = append(tree, nodes)
+	}
+	return tree
+}
+
+// chain applies steps chain hashes to a value that is start steps into chain i of a leaf
+func (s hashScheme) chain(publicSeed []byte, leaf uint32, i uint32, value []byte, start int, steps int) []byte {
+	value = append([]byte(nil), value...)
+	for step := start; step < start+steps; step++ {
+		value = s.hashTo(publicSeed, xmssTagChain, leaf, i, uint32(step), value)
+	}
+	return value
+}
+
+// hashTo hashes the inputs keyed with the public seed and the address of the value
+func (s hashScheme) hashTo(publicSeed []byte, tag byte, a uint32, b uint32, c uint32, inputs ...[]byte) []byte {
+	hash := zkx_algorithms.HashTypes[s.hash]()
+	hash.Write(publicSeed)
+	hash.Write([]byte{tag})
+	hash.Write(binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, a), b), c))
+	for _, input := range inputs {
+		hash.Write(input)
+	}
+	return hash.Sum(nil)
+}
+
+// secret derives secret material at an address from the secret seed
+func (s hashScheme) secret(seed *zkx_types.SecretKey, tag byte, a uint32, b uint32, input []byte) []byte {
+	mac := hmac.New(zkx_algorithms.HashTypes[s.hash], seed.Bytes())
+	mac.Write([]byte{tag})
+	mac.Write(binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, a), b))
+	mac.Write(input)
+	return mac.Sum(nil)
+}
+
+// wotsDigits splits a digest into base-w digits followed by the digits of its checksum
+func wotsDigits(digest []byte) []int {
+	digits := make([]int, 0, wotsLen)
+	checksum := wotsMaxSum
+	for _, b := range digest {
+		digits = append(digits, int(b>>4), int(b&0x0f))
+		checksum -= int(b>>4) + int(b&0x0f)
+	}
+	return append(digits, checksum>>8&0x0f, checksum>>4&0x0f, checksum&0x0f)
+}
@@ -0,0 +1,163 @@
+package core
+
+import (
+	"bytes"                                   // Import package for byte slice comparison
+	"errors"                                  // Import package for error handling
+	"path/filepath"                           // Import package for file paths
+	"sync"                                    // Import package for synchronization primitives
+	"testing"                                 // Import package for testing
+	zkx_errors "tmp/src/ZeroKnowledge/errors" // Import Zero Knowledge errors
+	zkx_models "tmp/src/ZeroKnowledge/models" // Import Zero Knowledge models
+)
+
+// testHashKey generates the smallest hash-based key
+func testHashKey(t *testing.T, z *ZeroKnowledge) *zkx_models.HashKey {
+	t.Helper()
+	key, err := z.GenerateHashKey("XMSS-SHA2_10_256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestHashSignerRoundTrip(t *testing.T) {
+	z := newTestZK(t)
+	state := NewMemoryHashKeyState()
+	signer, err := NewHashSigner(testHashKey(t, z), state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := signer.Sign("login 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := signer.Sign("login 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !z.Verify(*first, signer.Signature(), nil) || !z.Verify(*second, signer.Signature(), nil) {
+		t.Fatal("hash-based proof does not verify")
+	}
+	if first.Proof.Hash.Index == second.Proof.Hash.Index {
+		t.Fatal("two proofs used the same leaf")
+	}
+
+	// The last leaf signs once, then the key is exhausted
+	state.next = 1<<10 - 1
+	if _, err := signer.Sign("last"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := signer.Sign("too many"); !errors.Is(err, zkx_errors.ErrHashKeyExhausted) {
+		t.Fatalf("exhausted key gave %v", err)
+	}
+}
+
+func TestHashSignerRejectsTampering(t *testing.T) {
+	z := newTestZK(t)
+	signer, err := NewHashSigner(testHashKey(t, z), NewMemoryHashKeyState())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := signer.Sign("login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := signer.Signature()
+	if z.Verify(*signed, signature, "other") {
+		t.Fatal("hash-based proof verifies over other data")
+	}
+
+	tampered := *signed.Proof.Hash
+	tampered.Chains = append([][]byte(nil), tampered.Chains...)
+	tampered.Chains[3] = append([]byte(nil), tampered.Chains[3]...)
+	tampered.Chains[3][0] ^= 1
+	forged := *signed
+	forged.Proof.Hash = &tampered
+	if z.Verify(forged, signature, nil) {
+		t.Fatal("hash-based proof verifies with a tampered chain")
+	}
+	tampered = *signed.Proof.Hash
+	tampered.Index ^= 1
+	forged.Proof.Hash = &tampered
+	if z.Verify(forged, signature, nil) {
+		t.Fatal("hash-based proof verifies at another leaf")
+	}
+	tampered = *signed.Proof.Hash
+	tampered.Path = tampered.Path[1:]
+	forged.Proof.Hash = &tampered
+	if z.Verify(forged, signature, nil) {
+		t.Fatal("hash-based proof verifies with a short path")
+	}
+	forged.Proof.Hash = nil
+	if z.Verify(forged, signature, nil) {
+		t.Fatal("proof without a hash signature verifies against a hash-based key")
+	}
+}
+
+func TestFileHashKeyStateIsShared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	root := []byte("root of the key")
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seen := make(map[uint64]bool)
+	for worker := 0; worker < 4; worker++ {
+		// Separate states stand in for separate processes, only the file lock keeps them apart
+		state, err := NewFileHashKeyState(path, root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				index, err := state.Reserve()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				if seen[index] {
+					t.Errorf("leaf %d was handed out twice", index)
+				}
+				seen[index] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	state, err := NewFileHashKeyState(path, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index, err := state.Reserve(); err != nil || index != 100 {
+		t.Fatalf("reopened state gave leaf %d, %v", index, err)
+	}
+}
+
+// Regression: a state file must not hand out leaves for a key other than the one it was created for
+func TestFileHashKeyStateBelongsToOneKey(t *testing.T) {
+	z := newTestZK(t)
+	key := testHashKey(t, z)
+	other := testHashKey(t, z)
+	if bytes.Equal(key.Root, other.Root) {
+		t.Fatal("two generated keys share a root")
+	}
+	path := filepath.Join(t.TempDir(), "state")
+	state, err := NewFileHashKeyState(path, key.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index, err := state.Reserve(); err != nil || index != 0 {
+		t.Fatalf("first reservation gave leaf %d, %v", index, err)
+	}
+	if _, err := NewFileHashKeyState(path, other.Root); err == nil {
+		t.Fatal("opened the state of a key for another key")
+	}
+	foreign := &FileHashKeyState{Path: path, Root: other.Root}
+	if _, err := foreign.Reserve(); err == nil {
+		t.Fatal("reserved a leaf of a key for another key")
+	}
+	if index, err := state.Reserve(); err != nil || index != 1 {
+		t.Fatalf("second reservation gave leaf %d, %v", index, err)
+	}
+}
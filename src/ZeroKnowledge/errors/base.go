@@ -1 +1,41 @@
 package errors
+
+import (
+	"errors" // Import package for error handling
+)
+
+// Errors returned by the Zero Knowledge core, compare them with errors.Is
+var (
+	ErrMissingContext    = errors.New("Proof carries no context")                         // The proof is not bound to any context
+	ErrWrongAudience     = errors.New("Proof is for another audience")                    // The proof was made for another relying party
+	ErrNotYetValid       = errors.New("Proof is not valid yet")                           // The proof was issued in the future
+	ErrProofExpired      = errors.New("Proof has expired")                                // The proof is past its expiry
+	ErrUnknownNonce      = errors.New("Proof nonce was never issued")                     // The nonce did not come from this verifier
+	ErrNonceReused       = errors.New("Proof nonce was already used")                     // The proof is a replay
+	ErrInvalidProof      = errors.New("Invalid proof")                                    // The proof does not verify
+	ErrChannelBinding    = errors.New("Proof is bound to another channel")                // The session differs from the one the proof commits to
+	ErrServerNotPinned   = errors.New("Server signature does not match the pinned one")   // The server is not the one the client trusts
+	ErrServerProof       = errors.New("Invalid server proof")                             // The server could not prove knowledge of its secret
+	ErrProofReplayed     = errors.New("Proof was already seen")                           // The exact same proof was presented before
+	ErrReplayStoreFull   = errors.New("Replay store is full")                             // No proof can be remembered until older ones expire
+	ErrTreeHead          = errors.New("Invalid signed tree head")                         // The tree head is not signed by the pinned log
+	ErrLogInconsistent   = errors.New("Transparency log is inconsistent")                 // The log rewrote history the client has seen
+	ErrNotIncluded       = errors.New("Entry is not included in the log")                 // The inclusion proof does not lead to the tree head
+	ErrEntryMismatch     = errors.New("Logged signature differs from ours")               // The server registered another signature for the user
+	ErrKeyImageUsed      = errors.New("Key image was already used")                       // The member already signed within this scope
+	ErrUnknownChallenge  = errors.New("Challenge was not issued or was already used")     // The challenge cannot be consumed
+	ErrChallengeExpired  = errors.New("Challenge has expired")                            // The challenge was answered too late
+	ErrChallengeSeal     = errors.New("Challenge seal is invalid")                        // The challenge was not issued by this server or was altered
+	ErrChallengeBinding  = errors.New("Challenge is bound to another session")            // The challenge was issued to another user or session
+	ErrPuzzleInvalid     = errors.New("Puzzle was not issued for this challenge")         // The puzzle was forged, altered or belongs to another challenge
+	ErrPuzzleExpired     = errors.New("Puzzle has expired")                               // The puzzle was solved too late
+	ErrPuzzleUnsolved    = errors.New("Puzzle solution is wrong")                         // The nonce does not reach the difficulty
+	ErrPuzzleSpent       = errors.New("Puzzle was already used")                          // The solution was presented before
+	ErrHashKeyExhausted  = errors.New("Hash-based key has no one-time keys left")         // Every leaf of the tree has signed, register a new key
+	ErrCertificateProof  = errors.New("Certificate is not signed by its issuer")          // The certificate was forged or altered
+	ErrCertificateTime   = errors.New("Certificate is outside its validity period")       // The certificate expired or is not valid yet
+	ErrCertificateChain  = errors.New("Certificate chain is broken")                      // An issuer is missing, not an authority or the chain is too long
+	ErrUntrustedRoot     = errors.New("Certificate chain does not end at a trusted root") // No pinned root issued the last certificate
+	ErrMembershipRevoked = errors.New("Membership was revoked")                           // The element of the witness was removed from the accumulator
+	ErrStaleEpoch        = errors.New("Accumulator epoch is not current")                 // The proof or witness was made for an older epoch
+)
@@ -0,0 +1,75 @@
+// Package timing measures whether an operation takes longer for some inputs than for others,
+// it is internal so that only the tests of this module run it
+package timing
+
+import (
+	"crypto/rand" // Package for secure random number generation
+	"math"        // Package for square roots and absolute values
+	"sort"        // Package for sorting the measurements
+	"time"        // Package for measuring durations
+)
+
+// Threshold is the |t| above which a Leakage result indicates a leak with high confidence
+const Threshold = 4.5
+
+// timingCrops are the percentiles at which measurements are cropped before testing, 1 keeps them all
+var timingCrops = []float64{1, 0.99, 0.95, 0.9, 0.75, 0.5}
+
+// Leakage runs a dudect-style test: prepare builds an operation for input class 0 or 1, the classes
+// are interleaved at random and timed, and the largest Welch t statistic over all croppings is returned
+func Leakage(samples int, prepare func(class int) func()) float64 {
+	classes := make([]int, samples)
+	coins := make([]byte, samples)
+	if _, err := rand.Read(coins); err != nil {
+		return math.Inf(1) // Without random interleaving the test would be meaningless
+	}
+	operations := make([]func(), samples)
+	for i := range operations {
+		classes[i] = int(coins[i] & 1)
+		operations[i] = prepare(classes[i]) // Prepare outside the timed region
+	}
+
+	durations := make([]float64, samples)
+	for i, operation := range operations {
+		start := time.Now()
+		operation()
+		durations[i] = float64(time.Since(start).Nanoseconds())
+	}
+
+	sorted := append([]float64(nil), durations...)
+	sort.Float64s(sorted)
+	worst := 0.0
+	for _, crop := range timingCrops {
+		limit := sorted[int(crop*float64(len(sorted)-1))]
+		if t := math.Abs(welchT(durations, classes, limit)); t > worst {
+			worst = t
+		}
+	}
+	return worst
+}
+
+// welchT computes the Welch t statistic between the two classes over durations up to the limit
+func welchT(durations []float64, classes []int, limit float64) float64 {
+	var count [2]float64
+	var mean [2]float64
+	var m2 [2]float64
+	for i, duration := range durations {
+		if duration > limit {
+			continue
+		}
+		// Welford's online update keeps the variance numerically stable
+		class := classes[i]
+		count[class]++
+		delta := duration - mean[class]
+		mean[class] += delta / count[class]
+		m2[class] += delta * (duration - mean[class])
+	}
+	if count[0] < 2 || count[1] < 2 {
+		return 0
+	}
+	variance := m2[0]/(count[0]-1)/count[0] + m2[1]/(count[1]-1)/count[1]
+	if variance == 0 {
+		return 0
+	}
+	return (mean[0] - mean[1]) / math.Sqrt(variance)
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"encoding/json" // Import package for JSON encoding and decoding
+	"time"          // Import package for handling time
+)
+
+// Define AccumulatorEpoch struct, the accumulator value the issuer publishes after every revocation
+type AccumulatorEpoch struct {
+	Params  ZeroKnowledgeParams    // Parameters for zero-knowledge proofs
+	Epoch   uint64                 // Number of revocations so far
+	Value   []byte                 // Marshalled accumulator point
+	Revoked []byte                 // Element revoked by this epoch, empty for the first epoch
+	Issued  time.Time              // Time the epoch was published
+	Issuer  ZeroKnowledgeSignature // Signature of the issuer
+	Proof   ZeroKnowledgeProof     // Proof of the issuer over the epoch
+}
+
+// Define MembershipWitness struct, what a member needs to prove it is not revoked
+type MembershipWitness struct {
+	Epoch   uint64 // Epoch the witness is valid for
+	Element []byte // Revocation handle of the member, kept secret until it is revoked
+	Binding []byte // Marshalled binding of Element to the login signature of the member
+	Witness []byte // Marshalled point C with (key + Element)*C equal to the accumulator
+}
+
+// Define NonRevocationProof struct, a zero-knowledge proof that some unrevoked member is proving
+type NonRevocationProof struct {
+	Epoch      uint64     // Epoch the proof was made for
+	Group      [][]byte   // Bindings of the members the prover hides among
+	Blinded    []byte     // Marshalled witness times a random blinding factor
+	Complement []byte     // Marshalled point the issuer checks against its key
+	Proof      SigmaProof // Proof of knowledge of the blinding factor, the element and the login secret
+}
+
+// ToJSON converts AccumulatorEpoch to JSON
+func (epoch *AccumulatorEpoch) ToJSON() ([]byte, error) {
+	return json.Marshal(epoch) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to AccumulatorEpoch
+func (epoch *AccumulatorEpoch) FromJSON(data []byte) error {
+	return json.Unmarshal(data, epoch) // Parse JSON bytes into struct
+}
+
+// ToJSON converts MembershipWitness to JSON, store it like a secret
+func (witness *MembershipWitness) ToJSON() ([]byte, error) {
+	return json.Marshal(witness) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to MembershipWitness
+func (witness *MembershipWitness) FromJSON(data []byte) error {
+	return json.Unmarshal(data, witness) // Parse JSON bytes into struct
+}
+
+// ToJSON converts NonRevocationProof to JSON
+func (proof *NonRevocationProof) ToJSON() ([]byte, error) {
+	return json.Marshal(proof) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to NonRevocationProof
+func (proof *NonRevocationProof) FromJSON(data []byte) error {
+	return json.Unmarshal(data, proof) // Parse JSON bytes into struct
+}
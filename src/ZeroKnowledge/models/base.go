@@ -4,6 +4,7 @@ import (
 	"crypto/elliptic" // Import elliptic curve functions
 	"encoding/json"   // Import package for JSON encoding and decoding
 	"math/big"        // Import package for big integer arithmetic
+	"time"            // Import package for handling time
 )
 
 // Define ZeroKnowledgeParams struct
@@ -17,13 +18,25 @@ type ZeroKnowledgeParams struct {
 type ZeroKnowledgeSignature struct {
 	Params    ZeroKnowledgeParams // Parameters for zero-knowledge proofs
 	Signature []byte              // Signature data
+	Service   string              // Service the signature is a pseudonym for, empty for the master identity
+}
+
+// Define ProofContext struct, it binds a proof to one relying party and time window
+type ProofContext struct {
+	Audience string    // Relying party the proof is meant for
+	Nonce    []byte    // Nonce issued by the relying party
+	IssuedAt time.Time // Time the proof was created
+	Expires  time.Time // Time after which the proof must be refused
+	Binding  []byte    // Fingerprint of the channel the proof is bound to, empty for unbound proofs
 }
 
 // Define ZeroKnowledgeProof struct
 type ZeroKnowledgeProof struct {
-	Params ZeroKnowledgeParams // Parameters for zero-knowledge proofs
-	C      []byte              // Proof data
-	M      []byte              // Proof data
+	Params  ZeroKnowledgeParams // Parameters for zero-knowledge proofs
+	C       []byte              // Proof data
+	M       []byte              // Proof data
+	Context *ProofContext       // Context the proof is bound to, nil for proofs without one
+	Hash    *HashProof          // Hash-based signature replacing C and M, nil for curve proofs
 }
 
 // Define ZeroKnowledgeData struct
@@ -62,6 +75,16 @@ func (signature *ZeroKnowledgeSignature) FromJSON(data []byte) error {
 	return json.Unmarshal(data, signature) // Parse JSON bytes into struct
 }
 
+// ToJSON converts ProofContext to JSON
+func (context *ProofContext) ToJSON() ([]byte, error) {
+	return json.Marshal(context) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to ProofContext
+func (context *ProofContext) FromJSON(data []byte) error {
+	return json.Unmarshal(data, context) // Parse JSON bytes into struct
+}
+
 // ToJSON converts ZeroKnowledgeProof to JSON
 func (proof *ZeroKnowledgeProof) ToJSON() ([]byte, error) {
 	return json.Marshal(proof) // Convert struct to JSON bytes
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/json" // Import package for JSON encoding and decoding
+)
+
+// Define BlindCommitment struct, the two nonce commitments sent by the signer
+type BlindCommitment struct {
+	R0 []byte // Commitment of the first session
+	R1 []byte // Commitment of the second session
+}
+
+// Define BlindSignerState struct, it must never leave the signer
+type BlindSignerState struct {
+	K0 []byte // Secret nonce of the first session
+	K1 []byte // Secret nonce of the second session
+}
+
+// Define BlindChallenge struct, the two blinded challenges sent by the user
+type BlindChallenge struct {
+	C0 []byte // Blinded challenge of the first session
+	C1 []byte // Blinded challenge of the second session
+}
+
+// Define BlindUserState struct, the blinding factors kept by the user
+type BlindUserState struct {
+	Data   string   // Data being signed
+	Alphas [][]byte // Additive blinding of the response, one per session
+	Betas  [][]byte // Additive blinding of the challenge, one per session
+	C      [][]byte // Unblinded challenge, one per session
+}
+
+// Define BlindResponse struct, the signer answer for the session it picked
+type BlindResponse struct {
+	Session int    // Session completed by the signer, 0 or 1
+	M       []byte // Response for that session
+}
+
+// ToJSON converts BlindCommitment to JSON
+func (commitment *BlindCommitment) ToJSON() ([]byte, error) {
+	return json.Marshal(commitment) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to BlindCommitment
+func (commitment *BlindCommitment) FromJSON(data []byte) error {
+	return json.Unmarshal(data, commitment) // Parse JSON bytes into struct
+}
+
+// ToJSON converts BlindChallenge to JSON
+func (challenge *BlindChallenge) ToJSON() ([]byte, error) {
+	return json.Marshal(challenge) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to BlindChallenge
+func (challenge *BlindChallenge) FromJSON(data []byte) error {
+	return json.Unmarshal(data, challenge) // Parse JSON bytes into struct
+}
+
+// ToJSON converts BlindResponse to JSON
+func (response *BlindResponse) ToJSON() ([]byte, error) {
+	return json.Marshal(response) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to BlindResponse
+func (response *BlindResponse) FromJSON(data []byte) error {
+	return json.Unmarshal(data, response) // Parse JSON bytes into struct
+}
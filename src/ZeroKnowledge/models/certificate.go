@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/json" // Import package for JSON encoding and decoding
+	"time"          // Import package for handling time
+)
+
+// Define Certificate struct, an authority vouching for the signature of a subject
+type Certificate struct {
+	Serial     []byte                 // Random serial number of the certificate
+	Subject    string                 // Name of the subject
+	Signature  ZeroKnowledgeSignature // Signature the subject logs in with
+	Issuer     ZeroKnowledgeSignature // Signature of the authority that issued the certificate
+	NotBefore  time.Time              // Start of the validity period
+	NotAfter   time.Time              // End of the validity period
+	Attributes map[string]string      // Attributes the authority vouches for
+	Authority  bool                   // Whether the subject may issue certificates itself
+	Proof      ZeroKnowledgeProof     // Proof of the issuer over the content of the certificate
+}
+
+// Define CertifiedLogin struct, a login proof together with the chain certifying its signature
+type CertifiedLogin struct {
+	Chain []Certificate     // Certificates from the subject up to the one issued by a root
+	Login ZeroKnowledgeData // Login proof of the subject
+}
+
+// ToJSON converts Certificate to JSON
+func (certificate *Certificate) ToJSON() ([]byte, error) {
+	return json.Marshal(certificate) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to Certificate
+func (certificate *Certificate) FromJSON(data []byte) error {
+	return json.Unmarshal(data, certificate) // Parse JSON bytes into struct
+}
+
+// ToJSON converts CertifiedLogin to JSON
+func (login *CertifiedLogin) ToJSON() ([]byte, error) {
+	return json.Marshal(login) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to CertifiedLogin
+func (login *CertifiedLogin) FromJSON(data []byte) error {
+	return json.Unmarshal(data, login) // Parse JSON bytes into struct
+}
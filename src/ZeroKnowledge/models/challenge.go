@@ -0,0 +1,31 @@
+package models
+
+import (
+	"encoding/base64" // Import package for base64 encoding
+	"encoding/json"   // Import package for JSON encoding and decoding
+	"time"            // Import package for handling time
+)
+
+// Define Challenge struct, a single-use challenge issued to one session of a user
+type Challenge struct {
+	User    string    // User the challenge was issued to
+	Session string    // Session the challenge was issued for
+	Value   []byte    // Random challenge bytes
+	Issued  time.Time // Time the challenge was issued
+	Expires time.Time // Time after which the challenge is refused
+}
+
+// Data returns the challenge in the form the client signs it
+func (challenge *Challenge) Data() string {
+	return base64.StdEncoding.EncodeToString(challenge.Value)
+}
+
+// ToJSON converts Challenge to JSON
+func (challenge *Challenge) ToJSON() ([]byte, error) {
+	return json.Marshal(challenge) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to Challenge
+func (challenge *Challenge) FromJSON(data []byte) error {
+	return json.Unmarshal(data, challenge) // Parse JSON bytes into struct
+}
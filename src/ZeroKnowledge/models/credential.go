@@ -0,0 +1,93 @@
+package models
+
+import (
+	"encoding/json" // Import package for JSON encoding and decoding
+)
+
+// Define Attribute struct
+type Attribute struct {
+	Name  string // Name of the attribute, e.g. "role"
+	Value string // Value of the attribute, e.g. "admin"
+}
+
+// Define CredentialToken struct, one commitment of a credential that is shown once
+type CredentialToken struct {
+	Blinding    []byte             // Blinding scalar of the commitment, chosen by the holder
+	Commitment  []byte             // Vector Pedersen commitment to the attributes
+	IssuerProof ZeroKnowledgeProof // Issuer proof over the commitment
+}
+
+// Define Credential struct, kept by the holder
+type Credential struct {
+	Params     ZeroKnowledgeParams    // Parameters for zero-knowledge proofs
+	Attributes []Attribute            // Every attribute of the holder
+	Issuer     ZeroKnowledgeSignature // Signature of the issuer
+	Tokens     []CredentialToken      // Unused commitments, each presentation uses up one
+}
+
+// Define CredentialRequest struct, sent by the holder to the issuer
+type CredentialRequest struct {
+	Params     ZeroKnowledgeParams  // Parameters for zero-knowledge proofs
+	Attributes []Attribute          // Attributes the issuer attests
+	Blinded    [][]byte             // Blinding term of each commitment, a multiple of the blinding generator
+	Proofs     []ZeroKnowledgeProof // Proof of knowledge of each blinding
+}
+
+// Define CredentialIssuance struct, the issuer answer to a request
+type CredentialIssuance struct {
+	Issuer ZeroKnowledgeSignature // Signature of the issuer
+	Proofs []ZeroKnowledgeProof   // Issuer proof over each commitment of the request
+}
+
+// Define CredentialPresentation struct, sent to a verifier
+type CredentialPresentation struct {
+	Params      ZeroKnowledgeParams    // Parameters for zero-knowledge proofs
+	Commitment  []byte                 // Vector Pedersen commitment to the attributes
+	Issuer      ZeroKnowledgeSignature // Signature of the issuer
+	IssuerProof ZeroKnowledgeProof     // Issuer proof over the commitment
+	Disclosed   []Attribute            // Attributes revealed to the verifier
+	Hidden      []string               // Names of the attributes kept secret
+	Data        string                 // Challenge the presentation is bound to
+	C           []byte                 // Proof data
+	Responses   [][]byte               // One response per hidden attribute, then one for the blinding
+}
+
+// ToJSON converts Credential to JSON
+func (credential *Credential) ToJSON() ([]byte, error) {
+	return json.Marshal(credential) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to Credential
+func (credential *Credential) FromJSON(data []byte) error {
+	return json.Unmarshal(data, credential) // Parse JSON bytes into struct
+}
+
+// ToJSON converts CredentialPresentation to JSON
+func (presentation *CredentialPresentation) ToJSON() ([]byte, error) {
+	return json.Marshal(presentation) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to CredentialPresentation
+func (presentation *CredentialPresentation) FromJSON(data []byte) error {
+	return json.Unmarshal(data, presentation) // Parse JSON bytes into struct
+}
+
+// ToJSON converts CredentialRequest to JSON
+func (request *CredentialRequest) ToJSON() ([]byte, error) {
+	return json.Marshal(request) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to CredentialRequest
+func (request *CredentialRequest) FromJSON(data []byte) error {
+	return json.Unmarshal(data, request) // Parse JSON bytes into struct
+}
+
+// ToJSON converts CredentialIssuance to JSON
+func (issuance *CredentialIssuance) ToJSON() ([]byte, error) {
+	return json.Marshal(issuance) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to CredentialIssuance
+func (issuance *CredentialIssuance) FromJSON(data []byte) error {
+	return json.Unmarshal(data, issuance) // Parse JSON bytes into struct
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/json" // Import package for JSON encoding and decoding
+	"time"          // Import package for handling time
+)
+
+// Define DeviceKey struct, one of the signatures an account can log in with
+type DeviceKey struct {
+	ID         string                 // Identifier of the device within the account
+	Signature  ZeroKnowledgeSignature // Signature of the device secret
+	EnrolledBy string                 // Device that enrolled this one, empty for the first device
+	Enrolled   time.Time              // Time of the enrollment
+	Revoked    bool                   // Whether the device may no longer log in
+}
+
+// Define DeviceEnrollment struct, sent to add a new device to an account
+type DeviceEnrollment struct {
+	DeviceID      string                 // Identifier of the new device
+	Signature     ZeroKnowledgeSignature // Signature of the new device secret
+	Enroller      string                 // Identifier of the existing device vouching for the new one
+	Data          string                 // Challenge the enrollment is bound to
+	EnrollerProof ZeroKnowledgeProof     // Proof by the existing device over the enrollment
+	DeviceProof   ZeroKnowledgeProof     // Proof that the new device holds its secret
+}
+
+// ToJSON converts DeviceKey to JSON
+func (device *DeviceKey) ToJSON() ([]byte, error) {
+	return json.Marshal(device) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to DeviceKey
+func (device *DeviceKey) FromJSON(data []byte) error {
+	return json.Unmarshal(data, device) // Parse JSON bytes into struct
+}
+
+// ToJSON converts DeviceEnrollment to JSON
+func (enrollment *DeviceEnrollment) ToJSON() ([]byte, error) {
+	return json.Marshal(enrollment) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to DeviceEnrollment
+func (enrollment *DeviceEnrollment) FromJSON(data []byte) error {
+	return json.Unmarshal(data, enrollment) // Parse JSON bytes into struct
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json" // Import package for JSON encoding and decoding
+)
+
+// Define Ciphertext struct, an exponential ElGamal encryption of a small value
+type Ciphertext struct {
+	Params    ZeroKnowledgeParams    // Parameters for zero-knowledge proofs
+	Recipient ZeroKnowledgeSignature // Signature whose point the value is encrypted to
+	A         []byte                 // Randomness point r*G
+	B         []byte                 // Masked value v*G + r*Y
+}
+
+// Define Decryption struct, a decrypted value with a proof that the key holder decrypted correctly
+type Decryption struct {
+	Params ZeroKnowledgeParams // Parameters for zero-knowledge proofs
+	Value  int64               // Decrypted value
+	Share  []byte              // Decryption share x*A
+	C      []byte              // Proof data
+	M      []byte              // Proof data
+}
+
+// ToJSON converts Ciphertext to JSON
+func (ciphertext *Ciphertext) ToJSON() ([]byte, error) {
+	return json.Marshal(ciphertext) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to Ciphertext
+func (ciphertext *Ciphertext) FromJSON(data []byte) error {
+	return json.Unmarshal(data, ciphertext) // Parse JSON bytes into struct
+}
+
+// ToJSON converts Decryption to JSON
+func (decryption *Decryption) ToJSON() ([]byte, error) {
+	return json.Marshal(decryption) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to Decryption
+func (decryption *Decryption) FromJSON(data []byte) error {
+	return json.Unmarshal(data, decryption) // Parse JSON bytes into struct
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/json" // Import package for JSON encoding and decoding
+)
+
+// Define ClientHello struct, the first message of a mutual login
+type ClientHello struct {
+	Challenge []byte // Random challenge the server has to prove its identity over
+}
+
+// Define ServerHello struct, the answer of the server to a ClientHello
+type ServerHello struct {
+	Signature ZeroKnowledgeSignature // Signature of the server, clients compare it with their pinned copy
+	Token     string                 // Login token the client has to prove over
+	Audience  string                 // Audience the login proof of the client is bound to
+	Nonce     []byte                 // Nonce the login proof of the client has to be bound to
+	Proof     ZeroKnowledgeProof     // Proof of the server over the client challenge, the token, the audience and the nonce
+}
+
+// ToJSON converts ClientHello to JSON
+func (hello *ClientHello) ToJSON() ([]byte, error) {
+	return json.Marshal(hello) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to ClientHello
+func (hello *ClientHello) FromJSON(data []byte) error {
+	return json.Unmarshal(data, hello) // Parse JSON bytes into struct
+}
+
+// ToJSON converts ServerHello to JSON
+func (hello *ServerHello) ToJSON() ([]byte, error) {
+	return json.Marshal(hello) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to ServerHello
+func (hello *ServerHello) FromJSON(data []byte) error {
+	return json.Unmarshal(data, hello) // Parse JSON bytes into struct
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"                         // Import package for JSON encoding and decoding
+	"errors"                                // Import package for error handling
+	zkx_types "tmp/src/ZeroKnowledge/types" // Import Zero Knowledge types
+)
+
+// Define ExtendedKey struct, a node of a hierarchical identity tree
+type ExtendedKey struct {
+	Params            ZeroKnowledgeParams // Parameters for zero-knowledge proofs
+	Depth             uint8               // Number of derivations from the master key
+	ParentFingerprint []byte              // First bytes of the hash of the parent point, zero for the master key
+	Index             uint32              // Index of the key below its parent, hardened indices have the top bit set
+	ChainCode         []byte              // Chain code mixed into every child derivation
+	Key               *zkx_types.Scalar   // Identity scalar, nil for public extended keys
+	Public            []byte              // Marshalled public point, the signature of the key
+}
+
+// publicExtendedKey is the JSON form of an ExtendedKey, which never holds the identity scalar
+type publicExtendedKey struct {
+	Params            ZeroKnowledgeParams // Parameters for zero-knowledge proofs
+	Depth             uint8               // Number of derivations from the master key
+	ParentFingerprint []byte              // First bytes of the hash of the parent point
+	Index             uint32              // Index of the key below its parent
+	ChainCode         []byte              // Chain code mixed into every child derivation
+	Public            []byte              // Marshalled public point
+}
+
+// IsPrivate reports whether the extended key holds its identity scalar
+func (key *ExtendedKey) IsPrivate() bool {
+	return key.Key != nil
+}
+
+// MarshalJSON encodes a public extended key, private keys are only serialized with SerializeExtendedKey
+func (key ExtendedKey) MarshalJSON() ([]byte, error) {
+	if key.IsPrivate() {
+		return nil, errors.New("Private extended keys must be serialized with SerializeExtendedKey")
+	}
+	return json.Marshal(publicExtendedKey{
+		Params:            key.Params,
+		Depth:             key.Depth,
+		ParentFingerprint: key.ParentFingerprint,
+		Index:             key.Index,
+		ChainCode:         key.ChainCode,
+		Public:            key.Public,
+	})
+}
+
+// UnmarshalJSON decodes a public extended key
+func (key *ExtendedKey) UnmarshalJSON(data []byte) error {
+	public := publicExtendedKey{}
+	if err := json.Unmarshal(data, &public); err != nil {
+		return err
+	}
+	*key = ExtendedKey{
+		Params:            public.Params,
+		Depth:             public.Depth,
+		ParentFingerprint: public.ParentFingerprint,
+		Index:             public.Index,
+		ChainCode:         public.ChainCode,
+		Public:            public.Public,
+	}
+	return nil
+}
+
+// ToJSON converts ExtendedKey to JSON, it fails for private keys
+func (key *ExtendedKey) ToJSON() ([]byte, error) {
+	return json.Marshal(key) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to a public ExtendedKey
+func (key *ExtendedKey) FromJSON(data []byte) error {
+	return json.Unmarshal(data, key) // Parse JSON bytes into struct
+}
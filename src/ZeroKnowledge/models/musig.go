@@ -0,0 +1,79 @@
+package models
+
+import (
+	"encoding/json" // Import package for JSON encoding and decoding
+)
+
+// Define MuSigKeyAggregation struct
+type MuSigKeyAggregation struct {
+	Signatures   [][]byte               // Sorted signature points of the co-signers
+	Coefficients [][]byte               // Key aggregation coefficient of each co-signer
+	Signature    ZeroKnowledgeSignature // Aggregated signature shared by the group
+}
+
+// Define MuSigSecretNonce struct, it must never leave the signer
+type MuSigSecretNonce struct {
+	K1 []byte // First secret nonce scalar
+	K2 []byte // Second secret nonce scalar
+}
+
+// Define MuSigPublicNonce struct, the first round message
+type MuSigPublicNonce struct {
+	Signature []byte // Signature point of the co-signer
+	R1        []byte // Commitment to the first nonce
+	R2        []byte // Commitment to the second nonce
+}
+
+// Define MuSigSession struct
+type MuSigSession struct {
+	KeyAggregation MuSigKeyAggregation // Aggregated keys of the group
+	R1             []byte              // Sum of the first nonce commitments
+	R2             []byte              // Sum of the second nonce commitments
+	Data           string              // Data being signed by the group
+}
+
+// Define MuSigPartialSignature struct, the second round message
+type MuSigPartialSignature struct {
+	Signature []byte // Signature point of the co-signer
+	M         []byte // Partial response of the co-signer
+}
+
+// ToJSON converts MuSigKeyAggregation to JSON
+func (agg *MuSigKeyAggregation) ToJSON() ([]byte, error) {
+	return json.Marshal(agg) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to MuSigKeyAggregation
+func (agg *MuSigKeyAggregation) FromJSON(data []byte) error {
+	return json.Unmarshal(data, agg) // Parse JSON bytes into struct
+}
+
+// ToJSON converts MuSigPublicNonce to JSON
+func (nonce *MuSigPublicNonce) ToJSON() ([]byte, error) {
+	return json.Marshal(nonce) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to MuSigPublicNonce
+func (nonce *MuSigPublicNonce) FromJSON(data []byte) error {
+	return json.Unmarshal(data, nonce) // Parse JSON bytes into struct
+}
+
+// ToJSON converts MuSigSession to JSON
+func (session *MuSigSession) ToJSON() ([]byte, error) {
+	return json.Marshal(session) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to MuSigSession
+func (session *MuSigSession) FromJSON(data []byte) error {
+	return json.Unmarshal(data, session) // Parse JSON bytes into struct
+}
+
+// ToJSON converts MuSigPartialSignature to JSON
+func (partial *MuSigPartialSignature) ToJSON() ([]byte, error) {
+	return json.Marshal(partial) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to MuSigPartialSignature
+func (partial *MuSigPartialSignature) FromJSON(data []byte) error {
+	return json.Unmarshal(data, partial) // Parse JSON bytes into struct
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"encoding/json" // Import package for JSON encoding and decoding
+)
+
+// Define PseudonymLink struct, a proof that two identities share one master secret
+type PseudonymLink struct {
+	Params ZeroKnowledgeParams    // Parameters for zero-knowledge proofs
+	First  ZeroKnowledgeSignature // First linked identity
+	Second ZeroKnowledgeSignature // Second linked identity
+	C      []byte                 // Proof data
+	M      []byte                 // Proof data
+}
+
+// ToJSON converts PseudonymLink to JSON
+func (link *PseudonymLink) ToJSON() ([]byte, error) {
+	return json.Marshal(link) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to PseudonymLink
+func (link *PseudonymLink) FromJSON(data []byte) error {
+	return json.Unmarshal(data, link) // Parse JSON bytes into struct
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json" // Import package for JSON encoding and decoding
+	"time"          // Import package for handling time
+)
+
+// Define Puzzle struct, a hashcash puzzle issued together with a login challenge
+type Puzzle struct {
+	Algorithm  string    // Hash algorithm of ZeroKnowledge/algorithms the puzzle is solved with
+	Challenge  string    // Login challenge the puzzle belongs to
+	Seed       []byte    // Random seed of the puzzle
+	Difficulty int       // Number of leading zero bits the solution hash needs
+	Expires    time.Time // Time after which the puzzle is refused
+	Seal       []byte    // HMAC of the server over the puzzle
+}
+
+// Define PuzzleSolution struct, a puzzle together with the nonce that solves it
+type PuzzleSolution struct {
+	Puzzle Puzzle // Solved puzzle
+	Nonce  uint64 // Nonce the hash is computed with
+}
+
+// ToJSON converts Puzzle to JSON
+func (puzzle *Puzzle) ToJSON() ([]byte, error) {
+	return json.Marshal(puzzle) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to Puzzle
+func (puzzle *Puzzle) FromJSON(data []byte) error {
+	return json.Unmarshal(data, puzzle) // Parse JSON bytes into struct
+}
+
+// ToJSON converts PuzzleSolution to JSON
+func (solution *PuzzleSolution) ToJSON() ([]byte, error) {
+	return json.Marshal(solution) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to PuzzleSolution
+func (solution *PuzzleSolution) FromJSON(data []byte) error {
+	return json.Unmarshal(data, solution) // Parse JSON bytes into struct
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/json" // Import package for JSON encoding and decoding
+)
+
+// Define Ring struct, the set of signatures a ring signature hides its signer in
+type Ring struct {
+	Params  ZeroKnowledgeParams      // Parameters for zero-knowledge proofs
+	Scope   string                   // Action the ring is used for, key images only link within one scope
+	Members []ZeroKnowledgeSignature // Registered signatures of the members
+}
+
+// Define KeyImage struct, the same for every signature of one member within one scope
+type KeyImage struct {
+	Scope string // Scope the key image belongs to
+	Image []byte // Marshalled key image point
+}
+
+// Define RingSignature struct, an LSAG signature over the data
+type RingSignature struct {
+	Params   ZeroKnowledgeParams // Parameters for zero-knowledge proofs
+	Data     string              // Signed data
+	KeyImage KeyImage            // Key image of the signer
+	C        []byte              // Challenge of the first member
+	S        [][]byte            // Response of every member
+}
+
+// ToJSON converts Ring to JSON
+func (ring *Ring) ToJSON() ([]byte, error) {
+	return json.Marshal(ring) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to Ring
+func (ring *Ring) FromJSON(data []byte) error {
+	return json.Unmarshal(data, ring) // Parse JSON bytes into struct
+}
+
+// ToJSON converts KeyImage to JSON
+func (image *KeyImage) ToJSON() ([]byte, error) {
+	return json.Marshal(image) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to KeyImage
+func (image *KeyImage) FromJSON(data []byte) error {
+	return json.Unmarshal(data, image) // Parse JSON bytes into struct
+}
+
+// ToJSON converts RingSignature to JSON
+func (signature *RingSignature) ToJSON() ([]byte, error) {
+	return json.Marshal(signature) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to RingSignature
+func (signature *RingSignature) FromJSON(data []byte) error {
+	return json.Unmarshal(data, signature) // Parse JSON bytes into struct
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json" // Import package for JSON encoding and decoding
+	"time"          // Import package for handling time
+)
+
+// Define RotationProof struct, a joint proof of knowledge of the old and the new secret
+type RotationProof struct {
+	Params ZeroKnowledgeParams    // Parameters for zero-knowledge proofs
+	Old    ZeroKnowledgeSignature // Signature being replaced
+	New    ZeroKnowledgeSignature // Signature taking its place
+	User   string                 // User whose signature is rotated
+	Nonce  []byte                 // Single-use nonce issued by the registry
+	C      []byte                 // Shared challenge of both proofs
+	MOld   []byte                 // Response for the old secret
+	MNew   []byte                 // Response for the new secret
+}
+
+// Define RotationEvent struct, recorded whenever a registered signature is replaced
+type RotationEvent struct {
+	User      string                 // User whose signature was rotated
+	Old       ZeroKnowledgeSignature // Signature that was replaced
+	New       ZeroKnowledgeSignature // Signature now registered
+	Timestamp time.Time              // Time of the rotation
+}
+
+// ToJSON converts RotationProof to JSON
+func (proof *RotationProof) ToJSON() ([]byte, error) {
+	return json.Marshal(proof) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to RotationProof
+func (proof *RotationProof) FromJSON(data []byte) error {
+	return json.Unmarshal(data, proof) // Parse JSON bytes into struct
+}
+
+// ToJSON converts RotationEvent to JSON
+func (event *RotationEvent) ToJSON() ([]byte, error) {
+	return json.Marshal(event) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to RotationEvent
+func (event *RotationEvent) FromJSON(data []byte) error {
+	return json.Unmarshal(data, event) // Parse JSON bytes into struct
+}
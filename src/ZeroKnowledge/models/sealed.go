@@ -0,0 +1,23 @@
+package models
+
+import (
+	"encoding/json" // Import package for JSON encoding and decoding
+)
+
+// Define SealedChallenge struct, the content of a stateless challenge authenticated by the server
+type SealedChallenge struct {
+	User    string // User the challenge was issued to
+	Session string // Session the challenge is bound to
+	Random  []byte // Random part making each challenge unique
+	Expires int64  // Unix time after which the challenge is refused
+}
+
+// ToJSON converts SealedChallenge to JSON
+func (challenge *SealedChallenge) ToJSON() ([]byte, error) {
+	return json.Marshal(challenge) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to SealedChallenge
+func (challenge *SealedChallenge) FromJSON(data []byte) error {
+	return json.Unmarshal(data, challenge) // Parse JSON bytes into struct
+}
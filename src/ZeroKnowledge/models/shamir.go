@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/json" // Import package for JSON encoding and decoding
+)
+
+// Define ShamirBackup struct, the public part of a split identity secret
+type ShamirBackup struct {
+	Params      ZeroKnowledgeParams // Parameters for zero-knowledge proofs
+	Threshold   int                 // Number of shares needed to recover the secret
+	Commitments [][]byte            // Feldman commitments to the polynomial coefficients
+}
+
+// Define ShamirShare struct
+type ShamirShare struct {
+	Index    int    // Evaluation point of the share
+	Value    []byte // Polynomial value at the evaluation point
+	Checksum []byte // Checksum guarding against corrupted shares
+}
+
+// ToJSON converts ShamirBackup to JSON
+func (backup *ShamirBackup) ToJSON() ([]byte, error) {
+	return json.Marshal(backup) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to ShamirBackup
+func (backup *ShamirBackup) FromJSON(data []byte) error {
+	return json.Unmarshal(data, backup) // Parse JSON bytes into struct
+}
+
+// ToJSON converts ShamirShare to JSON
+func (share *ShamirShare) ToJSON() ([]byte, error) {
+	return json.Marshal(share) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to ShamirShare
+func (share *ShamirShare) FromJSON(data []byte) error {
+	return json.Unmarshal(data, share) // Parse JSON bytes into struct
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"encoding/json" // Import package for JSON encoding and decoding
+)
+
+// Kinds of SigmaStatement nodes
+const (
+	SigmaRelation = "relation" // Linear relation between witnesses and public points
+	SigmaAnd      = "and"      // Every child holds
+	SigmaOr       = "or"       // At least one child holds
+)
+
+// Define SigmaTerm struct, a witness times a base point
+type SigmaTerm struct {
+	Witness string // Name of the witness, equal names within one relation are the same secret
+	Base    []byte // Marshalled base point
+}
+
+// Define SigmaEquation struct, a public point written as a sum of terms
+type SigmaEquation struct {
+	Public []byte      // Marshalled public point
+	Terms  []SigmaTerm // Terms adding up to the public point
+}
+
+// Define SigmaStatement struct, a node of a compound statement
+type SigmaStatement struct {
+	Kind      string           // SigmaRelation, SigmaAnd or SigmaOr
+	Equations []SigmaEquation  // Equations of a relation
+	Children  []SigmaStatement // Parts of an AND or OR
+}
+
+// Define SigmaNode struct, the proof of one node of a statement
+type SigmaNode struct {
+	Challenge []byte      // Challenge of the node, only set on the children of an OR
+	Responses [][]byte    // Responses of a relation, one per witness in order of appearance
+	Children  []SigmaNode // Proofs of the parts of an AND or OR
+}
+
+// Define SigmaProof struct, a non-interactive proof of a compound statement
+type SigmaProof struct {
+	Params    ZeroKnowledgeParams // Parameters for zero-knowledge proofs
+	Data      string              // Data the proof is bound to
+	Challenge []byte              // Fiat-Shamir challenge of the whole statement
+	Root      SigmaNode           // Proof of the root of the statement
+}
+
+// ToJSON converts SigmaStatement to JSON
+func (statement *SigmaStatement) ToJSON() ([]byte, error) {
+	return json.Marshal(statement) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to SigmaStatement
+func (statement *SigmaStatement) FromJSON(data []byte) error {
+	return json.Unmarshal(data, statement) // Parse JSON bytes into struct
+}
+
+// ToJSON converts SigmaProof to JSON
+func (proof *SigmaProof) ToJSON() ([]byte, error) {
+	return json.Marshal(proof) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to SigmaProof
+func (proof *SigmaProof) FromJSON(data []byte) error {
+	return json.Unmarshal(data, proof) // Parse JSON bytes into struct
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"encoding/json" // Import package for JSON encoding and decoding
+	"time"          // Import package for handling time
+)
+
+// Define LogEntry struct, a registration recorded in the transparency log
+type LogEntry struct {
+	User      string                 // User the signature is registered for
+	Signature ZeroKnowledgeSignature // Registered signature
+	Timestamp time.Time              // Time of the registration
+	Previous  int                    // Index of the previous entry of the user, -1 for the first
+}
+
+// Define SignedTreeHead struct, the root of the log at some size signed by the log
+type SignedTreeHead struct {
+	Size      int                    // Number of entries in the tree
+	Root      []byte                 // Merkle root over the entries
+	Users     int                    // Number of users with an entry in the tree
+	UsersRoot []byte                 // Merkle root over the latest entry of every user, sorted by user
+	Timestamp time.Time              // Time the head was signed
+	Log       ZeroKnowledgeSignature // Signature of the log, pinned by clients
+	Proof     ZeroKnowledgeProof     // Proof of the log over the head
+}
+
+// Define InclusionProof struct, the audit path of one entry
+type InclusionProof struct {
+	Index int      // Index of the entry
+	Size  int      // Size of the tree the path leads up to
+	Path  [][]byte // Sibling hashes from the leaf up to the root
+}
+
+// Define ConsistencyProof struct, it shows that an older tree is a prefix of a newer one
+type ConsistencyProof struct {
+	OldSize int      // Size of the older tree
+	NewSize int      // Size of the newer tree
+	Path    [][]byte // Node hashes of the proof
+}
+
+// Define LogAudit struct, what the server hands a client so it can audit its own entries
+type LogAudit struct {
+	Head        SignedTreeHead   // Current signed tree head
+	Entries     []LogEntry       // Entries of the user from the one the client accepted last, oldest first
+	Inclusions  []InclusionProof // Proof that each entry is in the current tree
+	Latest      InclusionProof   // Proof that the last entry is the latest of the user in the users tree
+	Consistency ConsistencyProof // Proof from the head the client saw last to the current one
+}
+
+// ToJSON converts LogEntry to JSON
+func (entry *LogEntry) ToJSON() ([]byte, error) {
+	return json.Marshal(entry) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to LogEntry
+func (entry *LogEntry) FromJSON(data []byte) error {
+	return json.Unmarshal(data, entry) // Parse JSON bytes into struct
+}
+
+// ToJSON converts SignedTreeHead to JSON
+func (head *SignedTreeHead) ToJSON() ([]byte, error) {
+	return json.Marshal(head) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to SignedTreeHead
+func (head *SignedTreeHead) FromJSON(data []byte) error {
+	return json.Unmarshal(data, head) // Parse JSON bytes into struct
+}
+
+// ToJSON converts InclusionProof to JSON
+func (proof *InclusionProof) ToJSON() ([]byte, error) {
+	return json.Marshal(proof) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to InclusionProof
+func (proof *InclusionProof) FromJSON(data []byte) error {
+	return json.Unmarshal(data, proof) // Parse JSON bytes into struct
+}
+
+// ToJSON converts ConsistencyProof to JSON
+func (proof *ConsistencyProof) ToJSON() ([]byte, error) {
+	return json.Marshal(proof) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to ConsistencyProof
+func (proof *ConsistencyProof) FromJSON(data []byte) error {
+	return json.Unmarshal(data, proof) // Parse JSON bytes into struct
+}
+
+// ToJSON converts LogAudit to JSON
+func (audit *LogAudit) ToJSON() ([]byte, error) {
+	return json.Marshal(audit) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to LogAudit
+func (audit *LogAudit) FromJSON(data []byte) error {
+	return json.Unmarshal(data, audit) // Parse JSON bytes into struct
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"encoding/json"                         // Import package for JSON encoding and decoding
+	zkx_types "tmp/src/ZeroKnowledge/types" // Import Zero Knowledge types
+)
+
+// Define HashKey struct, the private half of a stateful hash-based identity
+type HashKey struct {
+	Params ZeroKnowledgeParams  // Parameters, Algorithm names the scheme and Salt holds the public seed
+	Seed   *zkx_types.SecretKey // Secret seed every one-time key is derived from
+	Root   []byte               // Root of the tree of one-time keys, the public identity
+}
+
+// Define HashProof struct, a WOTS+ one-time signature with its XMSS authentication path
+type HashProof struct {
+	Index  uint32   // Leaf of the one-time key that signed
+	Random []byte   // Randomness the message digest is computed with
+	Chains [][]byte // One chain value per base-w digit of the digest and its checksum
+	Path   [][]byte // Sibling nodes from the leaf up to the root
+}
+
+// ToJSON converts HashProof to JSON
+func (proof *HashProof) ToJSON() ([]byte, error) {
+	return json.Marshal(proof) // Convert struct to JSON bytes
+}
+
+// FromJSON converts JSON data to HashProof
+func (proof *HashProof) FromJSON(data []byte) error {
+	return json.Unmarshal(data, proof) // Parse JSON bytes into struct
+}
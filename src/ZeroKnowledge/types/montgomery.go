@@ -0,0 +1,239 @@
+package types
+
+import (
+	"errors"    // Import package for error handling
+	"io"        // Import package for random sources
+	"math/big"  // Import package for big integer arithmetic, only on public values
+	"math/bits" // Import package for carry-propagating word arithmetic
+)
+
+// limbs is the number of 64-bit words of an element, enough for every supported curve
+const limbs = 4
+
+// Modulus holds the constants of Montgomery arithmetic modulo an odd public prime of up to 256 bits
+type Modulus struct {
+	m    [limbs]uint64 // Modulus as little-endian words
+	inv  uint64        // -m^-1 mod 2^64
+	rr   [limbs]uint64 // R^2 mod m, with R = 2^256
+	size int           // Byte length of an encoded element
+}
+
+// NewModulus precomputes the Montgomery constants of m
+func NewModulus(m *big.Int) (*Modulus, error) {
+	if m.Sign() <= 0 || m.Bit(0) == 0 || m.BitLen() > 64*limbs {
+		return nil, errors.New("Modulus must be odd and at most 256 bits")
+	}
+	mod := &Modulus{m: toLimbs(m), size: (m.BitLen() + 7) / 8}
+
+	// Newton iteration doubles the correct low bits of m^-1 each round
+	inv := uint64(1)
+	for i := 0; i < 7; i++ {
+		inv *= 2 - mod.m[0]*inv
+	}
+	mod.inv = -inv
+
+	rr := new(big.Int).Lsh(big.NewInt(1), 2*64*limbs)
+	mod.rr = toLimbs(rr.Mod(rr, m))
+	return mod, nil
+}
+
+// element is a value in Montgomery form, the representation of a Scalar
+type element struct {
+	v   [limbs]uint64 // x*R mod m
+	mod *Modulus      // Modulus of the element
+}
+
+// montMul sets z = x*y/R mod m using word-by-word Montgomery multiplication (CIOS)
+func (mod *Modulus) montMul(z, x, y *[limbs]uint64) {
+	var t [limbs + 2]uint64
+	for i := 0; i < limbs; i++ {
+		// t += x*y[i]
+		var c uint64
+		for j := 0; j < limbs; j++ {
+			hi, lo := bits.Mul64(x[j], y[i])
+			var cc uint64
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j], c = lo, hi
+		}
+		var cc uint64
+		t[limbs], cc = bits.Add64(t[limbs], c, 0)
+		t[limbs+1] = cc
+
+		// t = (t + q*m) / 2^64, with q chosen so the low word cancels
+		q := t[0] * mod.inv
+		hi, lo := bits.Mul64(q, mod.m[0])
+		_, cc = bits.Add64(lo, t[0], 0)
+		c = hi + cc
+		for j := 1; j < limbs; j++ {
+			hi, lo = bits.Mul64(q, mod.m[j])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1], c = lo, hi
+		}
+		t[limbs-1], cc = bits.Add64(t[limbs], c, 0)
+		t[limbs] = t[limbs+1] + cc
+	}
+	mod.reduce(z, &t)
+}
+
+// reduce sets z = t mod m for any t < 2m, without branching on t
+func (mod *Modulus) reduce(z *[limbs]uint64, t *[limbs + 2]uint64) {
+	var d [limbs]uint64
+	var b uint64
+	for j := 0; j < limbs; j++ {
+		d[j], b = bits.Sub64(t[j], mod.m[j], b)
+	}
+	_, b = bits.Sub64(t[limbs], 0, b)
+	mask := -b // All ones when t < m, keep t then
+	for j := 0; j < limbs; j++ {
+		z[j] = (t[j] & mask) | (d[j] &^ mask)
+	}
+}
+
+// add sets z = x + y mod m
+func (mod *Modulus) add(z, x, y *[limbs]uint64) {
+	var t [limbs + 2]uint64
+	var c uint64
+	for j := 0; j < limbs; j++ {
+		t[j], c = bits.Add64(x[j], y[j], c)
+	}
+	t[limbs] = c
+	mod.reduce(z, &t)
+}
+
+// sub sets z = x - y mod m
+func (mod *Modulus) sub(z, x, y *[limbs]uint64) {
+	var b, c uint64
+	for j := 0; j < limbs; j++ {
+		z[j], b = bits.Sub64(x[j], y[j], b)
+	}
+	mask := -b // All ones when the subtraction wrapped, add m back then
+	for j := 0; j < limbs; j++ {
+		z[j], c = bits.Add64(z[j], mod.m[j]&mask, c)
+	}
+}
+
+// setBytes loads a big-endian value of up to 64 bytes, reducing it modulo m
+func (e *element) setBytes(mod *Modulus, b []byte) error {
+	if len(b) > 16*limbs {
+		return errors.New("Value is too long")
+	}
+	var wide [2 * 8 * limbs]byte
+	copy(wide[len(wide)-len(b):], b)
+	hi, lo := bytesToLimbs(wide[:8*limbs]), bytesToLimbs(wide[8*limbs:])
+
+	// x = hi*2^256 + lo, so x*R = hi*R^2 + lo*R
+	var h, l [limbs]uint64
+	mod.montMul(&h, &hi, &mod.rr)
+	mod.montMul(&h, &h, &mod.rr)
+	mod.montMul(&l, &lo, &mod.rr)
+	mod.add(&e.v, &h, &l)
+	e.mod = mod
+	return nil
+}
+
+// bytes encodes the element as a fixed length big-endian value
+func (e *element) bytes() []byte {
+	var x [limbs]uint64
+	one := [limbs]uint64{1}
+	e.mod.montMul(&x, &e.v, &one)
+	out := make([]byte, 8*limbs)
+	for j := 0; j < limbs; j++ {
+		for k := 0; k < 8; k++ {
+			out[len(out)-1-8*j-k] = byte(x[j] >> (8 * k))
+		}
+	}
+	return out[len(out)-e.mod.size:]
+}
+
+// equal compares two elements in constant time
+func (e *element) equal(other *element) bool {
+	var acc uint64
+	for j := 0; j < limbs; j++ {
+		acc |= e.v[j] ^ other.v[j]
+	}
+	return isZeroWord(acc)
+}
+
+// invert sets e = x^(m-2), the inverse of a non-zero x; the exponent is public so the branches leak nothing
+func (e *element) invert(x *element) {
+	exponent := limbsToInt(x.mod.m)
+	exponent.Sub(exponent, big.NewInt(2))
+	one := [limbs]uint64{1}
+	var result [limbs]uint64
+	x.mod.montMul(&result, &x.mod.rr, &one) // R mod m, the Montgomery form of one
+	base := x.v
+	for i := exponent.BitLen() - 1; i >= 0; i-- {
+		x.mod.montMul(&result, &result, &result)
+		if exponent.Bit(i) == 1 {
+			x.mod.montMul(&result, &result, &base)
+		}
+	}
+	e.v, e.mod = result, x.mod
+}
+
+// random sets e to a uniformly random element by rejection sampling
+func (e *element) random(mod *Modulus, rand io.Reader) error {
+	buf := make([]byte, mod.size)
+	top := limbsToInt(mod.m).BitLen() % 8
+	for {
+		if _, err := io.ReadFull(rand, buf); err != nil {
+			return err
+		}
+		if top != 0 {
+			buf[0] &= byte(1<<top) - 1 // Drop the bits above the modulus
+		}
+		var padded [8 * limbs]byte
+		copy(padded[len(padded)-len(buf):], buf)
+		x := bytesToLimbs(padded[:])
+
+		// Only rejected samples influence the timing, and they are discarded
+		var b uint64
+		for j := 0; j < limbs; j++ {
+			_, b = bits.Sub64(x[j], mod.m[j], b)
+		}
+		if b == 1 {
+			mod.montMul(&e.v, &x, &mod.rr)
+			e.mod = mod
+			return nil
+		}
+	}
+}
+
+// isZeroWord reports whether a word is zero without branching on it
+func isZeroWord(w uint64) bool {
+	return (w|-w)>>63 == 0
+}
+
+// toLimbs converts a public big integer of up to 256 bits to little-endian words
+func toLimbs(x *big.Int) [limbs]uint64 {
+	var buf [8 * limbs]byte
+	x.FillBytes(buf[:])
+	return bytesToLimbs(buf[:])
+}
+
+// bytesToLimbs converts 32 big-endian bytes to little-endian words
+func bytesToLimbs(b []byte) [limbs]uint64 {
+	var x [limbs]uint64
+	for j := 0; j < limbs; j++ {
+		for k := 0; k < 8; k++ {
+			x[j] |= uint64(b[len(b)-1-8*j-k]) << (8 * k)
+		}
+	}
+	return x
+}
+
+// limbsToInt converts public little-endian words to a big integer
+func limbsToInt(x [limbs]uint64) *big.Int {
+	result := new(big.Int)
+	for j := limbs - 1; j >= 0; j-- {
+		result.Lsh(result, 64)
+		result.Or(result, new(big.Int).SetUint64(x[j]))
+	}
+	return result
+}
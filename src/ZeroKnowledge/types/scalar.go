@@ -0,0 +1,89 @@
+package types
+
+import (
+	"io" // Import package for random sources
+)
+
+// Scalar is an integer modulo the order of a curve, all of its operations run in constant time
+type Scalar struct {
+	e element // Montgomery representation
+}
+
+// NewScalar creates a Scalar from a big-endian value of up to 64 bytes, reduced modulo the order
+func NewScalar(order *Modulus, b []byte) (*Scalar, error) {
+	s := &Scalar{}
+	if err := s.e.setBytes(order, b); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// RandomScalar draws a uniformly random non-zero Scalar
+func RandomScalar(order *Modulus, rand io.Reader) (*Scalar, error) {
+	s := &Scalar{}
+	for {
+		if err := s.e.random(order, rand); err != nil {
+			return nil, err
+		}
+		if !s.IsZero() {
+			return s, nil
+		}
+	}
+}
+
+// Bytes encodes the Scalar as fixed length big-endian bytes
+func (s *Scalar) Bytes() []byte {
+	return s.e.bytes()
+}
+
+// Add sets s = x + y and returns s
+func (s *Scalar) Add(x, y *Scalar) *Scalar {
+	x.e.mod.add(&s.e.v, &x.e.v, &y.e.v)
+	s.e.mod = x.e.mod
+	return s
+}
+
+// Sub sets s = x - y and returns s
+func (s *Scalar) Sub(x, y *Scalar) *Scalar {
+	x.e.mod.sub(&s.e.v, &x.e.v, &y.e.v)
+	s.e.mod = x.e.mod
+	return s
+}
+
+// Mul sets s = x * y and returns s
+func (s *Scalar) Mul(x, y *Scalar) *Scalar {
+	x.e.mod.montMul(&s.e.v, &x.e.v, &y.e.v)
+	s.e.mod = x.e.mod
+	return s
+}
+
+// Invert sets s = 1/x and returns s, x must not be zero
+func (s *Scalar) Invert(x *Scalar) *Scalar {
+	s.e.invert(&x.e)
+	return s
+}
+
+// Equal compares two Scalars in constant time
+func (s *Scalar) Equal(other *Scalar) bool {
+	return s.e.equal(&other.e)
+}
+
+// IsZero reports whether the Scalar is zero in constant time
+func (s *Scalar) IsZero() bool {
+	return s.e.equal(&element{mod: s.e.mod})
+}
+
+// Destroy overwrites the Scalar with zero
+func (s *Scalar) Destroy() {
+	s.e.v = [limbs]uint64{}
+}
+
+// String redacts the Scalar, it usually holds a secret
+func (s Scalar) String() string {
+	return "Scalar(REDACTED)"
+}
+
+// GoString redacts the Scalar for the %#v verb
+func (s Scalar) GoString() string {
+	return "Scalar(REDACTED)"
+}
@@ -0,0 +1,13 @@
+package types
+
+import (
+	secret_types "tmp/src/Secret/types" // Import the shared SecretKey type
+)
+
+// SecretKey is the shared secret type, named here so that Zero Knowledge code keeps one types package
+type SecretKey = secret_types.SecretKey
+
+// NewSecretKey creates a SecretKey holding a copy of the given bytes
+func NewSecretKey(key []byte) *SecretKey {
+	return secret_types.NewSecretKey(key)
+}
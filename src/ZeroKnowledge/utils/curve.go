@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"crypto/elliptic" // Package for elliptic curve cryptography
+	"crypto/sha256"   // Package for SHA-256 hashing algorithm
+	"math/big"        // Package for arbitrary-precision arithmetic
+)
+
+// HashToCurve maps the provided values to a curve point with unknown discrete logarithm, using try-and-increment
+func HashToCurve(curve elliptic.Curve, values ...[]byte) (*big.Int, *big.Int) {
+	params := curve.Params()
+	three := big.NewInt(3)
+	for counter := 0; ; counter++ {
+		// Hash the values together with the counter into a candidate x coordinate
+		h := sha256.New()
+		h.Write([]byte("HashToCurve"))
+		for _, value := range values {
+			h.Write(IntToBytes(big.NewInt(int64(len(value))))) // Length prefix keeps the encoding unambiguous
+			h.Write(value)
+		}
+		h.Write(IntToBytes(big.NewInt(int64(counter))))
+		x := new(big.Int).SetBytes(h.Sum(nil))
+		x.Mod(x, params.P)
+
+		// y^2 = x^3 - 3x + b on the short Weierstrass curves we support
+		y2 := new(big.Int).Exp(x, three, params.P)
+		y2.Sub(y2, new(big.Int).Mul(three, x))
+		y2.Add(y2, params.B)
+		y2.Mod(y2, params.P)
+		y := new(big.Int).ModSqrt(y2, params.P)
+		if y == nil {
+			continue // Not a square, try the next counter
+		}
+
+		// Pick the even root so the mapping is deterministic
+		if y.Bit(0) == 1 {
+			y.Sub(params.P, y)
+		}
+		if curve.IsOnCurve(x, y) {
+			return x, y
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"bytes"         // Package for byte slice comparison
+	"crypto/sha256" // Package for SHA-256 hashing algorithm
+)
+
+// The Merkle tree follows RFC 9162: leaves and nodes are hashed with distinct prefixes and a tree
+// of n leaves splits at the largest power of two below n.
+
+// MerkleLeafHash hashes a leaf of the tree
+func MerkleLeafHash(data []byte) []byte {
+	hash := sha256.Sum256(append([]byte{0x00}, data...))
+	return hash[:]
+}
+
+// merkleNodeHash hashes two child nodes into their parent
+func merkleNodeHash(left []byte, right []byte) []byte {
+	buf := make([]byte, 0, 1+len(left)+len(right))
+	buf = append(append(append(buf, 0x01), left...), right...)
+	hash := sha256.Sum256(buf)
+	return hash[:]
+}
+
+// merkleSplit returns the largest power of two smaller than n, n must be at least 2
+func merkleSplit(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// MerkleRoot computes the root over the leaf hashes, the root of an empty tree is the hash of nothing
+func MerkleRoot(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		hash := sha256.Sum256(nil)
+		return hash[:]
+	case 1:
+		return leaves[0]
+	}
+	k := merkleSplit(len(leaves))
+	return merkleNodeHash(MerkleRoot(leaves[:k]), MerkleRoot(leaves[k:]))
+}
+
+// MerkleInclusionPath returns the audit path of the leaf at index in the tree over the leaf hashes
+func MerkleInclusionPath(leaves [][]byte, index int) [][]byte {
+	if len(leaves) <= 1 {
+		return nil
+	}
+	k := merkleSplit(len(leaves))
+	if index < k {
+		return append(MerkleInclusionPath(leaves[:k], index), MerkleRoot(leaves[k:]))
+	}
+	return append(MerkleInclusionPath(leaves[k:], index-k), MerkleRoot(leaves[:k]))
+}
+
+// VerifyMerkleInclusion checks that the leaf hash sits at index in the tree of the given size and root
+func VerifyMerkleInclusion(leaf []byte, index int, size int, path [][]byte, root []byte) bool {
+	if index < 0 || index >= size {
+		return false
+	}
+	fn, sn := index, size-1
+	r := leaf
+	for _, p := range path {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			r = merkleNodeHash(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = merkleNodeHash(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && bytes.Equal(r, root)
+}
+
+// MerkleConsistencyPath returns the proof that the tree over the first oldSize leaves is a prefix of the whole tree
+func MerkleConsistencyPath(leaves [][]byte, oldSize int) [][]byte {
+	if oldSize <= 0 || oldSize >= len(leaves) {
+		return nil
+	}
+	return merkleSubproof(leaves, oldSize, true)
+}
+
+// merkleSubproof implements SUBPROOF of RFC 9162, complete tells whether the old tree is a complete subtree
+func merkleSubproof(leaves [][]byte, m int, complete bool) [][]byte {
+	if m == len(leaves) {
+		if complete {
+			return nil
+		}
+		return [][]byte{MerkleRoot(leaves)}
+	}
+	k := merkleSplit(len(leaves))
+	if m <= k {
+		return append(merkleSubproof(leaves[:k], m, complete), MerkleRoot(leaves[k:]))
+	}
+	return append(merkleSubproof(leaves[k:], m-k, false), MerkleRoot(leaves[:k]))
+}
+
+// VerifyMerkleConsistency checks that the tree of oldSize leaves and oldRoot is a prefix of the tree of newSize leaves and newRoot
+func VerifyMerkleConsistency(oldSize int, newSize int, oldRoot []byte, newRoot []byte, path [][]byte) bool {
+	switch {
+	case oldSize < 0 || oldSize > newSize:
+		return false
+	case oldSize == newSize:
+		return len(path) == 0 && bytes.Equal(oldRoot, newRoot)
+	case oldSize == 0:
+		return len(path) == 0 // The empty tree is a prefix of every tree
+	}
+
+	// An old tree that is a complete subtree is its own first node
+	if oldSize&(oldSize-1) == 0 {
+		path = append([][]byte{oldRoot}, path...)
+	}
+	if len(path) == 0 {
+		return false
+	}
+	fn, sn := oldSize-1, newSize-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+	fr, sr := path[0], path[0]
+	for _, c := range path[1:] {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = merkleNodeHash(c, fr)
+			sr = merkleNodeHash(c, sr)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = merkleNodeHash(sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && bytes.Equal(fr, oldRoot) && bytes.Equal(sr, newRoot)
+}
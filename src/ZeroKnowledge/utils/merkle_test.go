@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"fmt"     // Package for formatted I/O
+	"testing" // Package for testing
+)
+
+// testLeaves returns the leaf hashes of a tree with n leaves
+func testLeaves(n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		leaves[i] = MerkleLeafHash([]byte(fmt.Sprint("leaf ", i)))
+	}
+	return leaves
+}
+
+func TestMerkleInclusion(t *testing.T) {
+	for size := 1; size <= 17; size++ {
+		leaves := testLeaves(size)
+		root := MerkleRoot(leaves)
+		larger := MerkleRoot(testLeaves(size + 1))
+		for index := 0; index < size; index++ {
+			path := MerkleInclusionPath(leaves, index)
+			if !VerifyMerkleInclusion(leaves[index], index, size, path, root) {
+				t.Fatalf("leaf %d of %d does not verify", index, size)
+			}
+			if VerifyMerkleInclusion(MerkleLeafHash([]byte("other")), index, size, path, root) {
+				t.Fatalf("other leaf verifies at %d of %d", index, size)
+			}
+			if size > 1 && VerifyMerkleInclusion(leaves[index], (index+1)%size, size, path, root) {
+				t.Fatalf("leaf %d of %d verifies at another index", index, size)
+			}
+			if VerifyMerkleInclusion(leaves[index], index, size+1, path, larger) {
+				t.Fatalf("leaf %d of %d verifies in a larger tree", index, size)
+			}
+		}
+	}
+	if VerifyMerkleInclusion(testLeaves(1)[0], -1, 1, nil, testLeaves(1)[0]) {
+		t.Fatal("negative index verifies")
+	}
+}
+
+func TestMerkleConsistency(t *testing.T) {
+	for newSize := 1; newSize <= 17; newSize++ {
+		leaves := testLeaves(newSize)
+		newRoot := MerkleRoot(leaves)
+		for oldSize := 0; oldSize <= newSize; oldSize++ {
+			oldRoot := MerkleRoot(leaves[:oldSize])
+			path := MerkleConsistencyPath(leaves, oldSize)
+			if !VerifyMerkleConsistency(oldSize, newSize, oldRoot, newRoot, path) {
+				t.Fatalf("tree of %d is not consistent with %d", oldSize, newSize)
+			}
+			if oldSize > 0 && oldSize < newSize {
+				forked := append(testLeaves(oldSize-1), MerkleLeafHash([]byte("forked")))
+				if VerifyMerkleConsistency(oldSize, newSize, MerkleRoot(forked), newRoot, path) {
+					t.Fatalf("forked tree of %d is consistent with %d", oldSize, newSize)
+				}
+			}
+		}
+	}
+	if VerifyMerkleConsistency(3, 2, nil, nil, nil) {
+		t.Fatal("larger old tree is consistent")
+	}
+}
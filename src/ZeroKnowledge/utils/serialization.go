@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"bytes"         // Package for byte buffers
 	"encoding/json" // Package for JSON encoding and decoding
 )
 
@@ -8,3 +9,20 @@ import (
 func dumpObject(dc interface{}) ([]byte, error) {
 	return json.Marshal(dc) // Marshal the JSON Dataclass into compressed JSON bytes
 }
+
+// CanonicalJSON encodes a value as compact JSON with sorted object keys, so equal values hash equally
+func CanonicalJSON(value interface{}) ([]byte, error) {
+	encoded, err := json.Marshal(value) // Encode the value with its own JSON rules first
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode into generic maps, which re-encode with sorted keys, keeping numbers verbatim
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return json.Marshal(generic)
+}